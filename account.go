@@ -0,0 +1,289 @@
+package buda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const PusherAuthEndpoint = "/pusher/auth"
+
+const (
+	OrderUpdatedEvent   = "order-updated"
+	BalanceUpdatedEvent = "balance-updated"
+	DepositUpdatedEvent = "deposit-updated"
+)
+
+// AccountFeed is implemented by Realtime, once subscribed to an account
+// channel, and by AccountPoller when websockets are not an option.
+type AccountFeed interface {
+	Events() <-chan Event
+	Run(ctx context.Context) error
+}
+
+type OrderUpdated struct {
+	Order   Order
+	channel string
+}
+
+type BalanceUpdated struct {
+	Balance Balance
+	channel string
+}
+
+type DepositUpdated struct {
+	Deposit Deposit
+	channel string
+}
+
+func (event *OrderUpdated) Channel() string   { return event.channel }
+func (event *BalanceUpdated) Channel() string { return event.channel }
+func (event *DepositUpdated) Channel() string { return event.channel }
+
+// ChannelAuthorizer signs a subscription to a private channel for the given
+// socket.
+type ChannelAuthorizer func(ctx context.Context, socketID string, channel string) (string, error)
+
+type channelAuthRequest struct {
+	SocketID    string `json:"socket_id"`
+	ChannelName string `json:"channel_name"`
+}
+
+// PusherAuthorizer returns a ChannelAuthorizer backed by a signed request to
+// PusherAuthEndpoint.
+func (client *APIClient) PusherAuthorizer() ChannelAuthorizer {
+	return func(ctx context.Context, socketID string, channel string) (string, error) {
+		var response struct {
+			Auth string `json:"auth"`
+		}
+
+		data, err := client.PostWithContext(ctx, PusherAuthEndpoint, &channelAuthRequest{SocketID: socketID, ChannelName: channel}, true)
+		if err != nil {
+			return "", err
+		}
+
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return "", err
+		}
+
+		if response.Auth == "" {
+			return "", fmt.Errorf("no auth signature for channel %s", channel)
+		}
+
+		return response.Auth, nil
+	}
+}
+
+func AccountChannel(pubsubKey string) string {
+	return "private-" + pubsubKey
+}
+
+// SubscribeAccount subscribes to a private account channel, authorizing it on
+// every (re)connect.
+func (realtime *Realtime) SubscribeAccount(ctx context.Context, channel string, authorize ChannelAuthorizer) error {
+	if authorize == nil {
+		return fmt.Errorf("private channel %s needs an authorizer", channel)
+	}
+	return realtime.subscribe(ctx, channel, subscription{decode: decodeAccountEvent, authorize: authorize})
+}
+
+func decodeAccountEvent(channel string, marketId string, message *pusherMessage) (Event, error) {
+	var target interface{}
+	var event Event
+
+	switch message.Event {
+	case OrderUpdatedEvent:
+		updated := &OrderUpdated{channel: channel}
+		target, event = &updated.Order, updated
+	case BalanceUpdatedEvent:
+		updated := &BalanceUpdated{channel: channel}
+		target, event = &updated.Balance, updated
+	case DepositUpdatedEvent:
+		updated := &DepositUpdated{channel: channel}
+		target, event = &updated.Deposit, updated
+	default:
+		return nil, nil
+	}
+
+	err := json.Unmarshal(message.payload(), target)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// AccountPoller is the polling counterpart of a private Realtime channel. It
+// watches balances, the latest orders of each market and the latest deposits
+// of each currency, and emits an event for everything that appeared or
+// changed since the previous poll. The first successful poll of each source
+// only records its state. Failed polls are reported on Errors when there is
+// room, dropped otherwise, and retried on the next tick.
+type AccountPoller struct {
+	client     *APIClient
+	interval   time.Duration
+	markets    []string
+	currencies []string
+	events     chan Event
+	errors     chan error
+	seen       map[string]map[string]string
+	mutex      sync.Mutex
+	started    bool
+}
+
+// NewAccountPoller polls every interval, or every DefaultPollInterval when
+// interval is not positive.
+func (client *APIClient) NewAccountPoller(interval time.Duration, markets []string, currencies []string) *AccountPoller {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return &AccountPoller{
+		client:     client,
+		interval:   interval,
+		markets:    markets,
+		currencies: currencies,
+		events:     make(chan Event),
+		errors:     make(chan error, 1),
+		seen:       make(map[string]map[string]string),
+	}
+}
+
+// Events is closed when Run returns.
+func (poller *AccountPoller) Events() <-chan Event {
+	return poller.events
+}
+
+// Errors is closed when Run returns.
+func (poller *AccountPoller) Errors() <-chan error {
+	return poller.errors
+}
+
+// Run polls until ctx is done. It can only be called once.
+func (poller *AccountPoller) Run(ctx context.Context) error {
+	poller.mutex.Lock()
+	started := poller.started
+	poller.started = true
+	poller.mutex.Unlock()
+
+	if started {
+		return ErrAlreadyRun
+	}
+	defer close(poller.events)
+	defer close(poller.errors)
+
+	ticker := time.NewTicker(poller.interval)
+	defer ticker.Stop()
+
+	for {
+		events, errs := poller.poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		for _, err := range errs {
+			select {
+			case poller.errors <- err:
+			default:
+			}
+		}
+
+		for _, event := range events {
+			select {
+			case poller.events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (poller *AccountPoller) poll(ctx context.Context) ([]Event, []error) {
+	var events []Event
+	var errs []error
+
+	balances, err := poller.client.GetBalancesWithContext(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("polling balances: %w", err))
+	} else {
+		items := make(map[string]interface{}, len(balances))
+		for i := range balances {
+			items[balances[i].ID] = &balances[i]
+		}
+		for _, id := range poller.changed("balances", items) {
+			events = append(events, &BalanceUpdated{Balance: *items[id].(*Balance)})
+		}
+	}
+
+	for _, marketId := range poller.markets {
+		orders, err := poller.client.OrdersPaginator(marketId, "").Next(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("polling %s orders: %w", marketId, err))
+			continue
+		}
+		items := make(map[string]interface{}, len(orders))
+		for i := range orders {
+			items[strconv.Itoa(orders[i].ID)] = &orders[i]
+		}
+		for _, id := range poller.changed("orders:"+marketId, items) {
+			events = append(events, &OrderUpdated{Order: *items[id].(*Order)})
+		}
+	}
+
+	for _, currency := range poller.currencies {
+		deposits, err := poller.client.DepositsPaginator(currency, "").Next(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("polling %s deposits: %w", currency, err))
+			continue
+		}
+		items := make(map[string]interface{}, len(deposits))
+		for i := range deposits {
+			items[strconv.Itoa(deposits[i].ID)] = &deposits[i]
+		}
+		for _, id := range poller.changed("deposits:"+currency, items) {
+			events = append(events, &DepositUpdated{Deposit: *items[id].(*Deposit)})
+		}
+	}
+
+	return events, errs
+}
+
+// changed records the state of a source and returns, in sorted order, the ids
+// whose encoded form differs from the previous poll. Decimals are compared
+// through their encoding since equal values are not == comparable.
+func (poller *AccountPoller) changed(source string, items map[string]interface{}) []string {
+	current := make(map[string]string, len(items))
+	for id, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		current[id] = string(data)
+	}
+
+	previous, seeded := poller.seen[source]
+	poller.seen[source] = current
+
+	if !seeded {
+		return nil
+	}
+
+	var ids []string
+	for id, fingerprint := range current {
+		if previous[id] != fingerprint {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,154 @@
+package buda
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIClient_PusherAuthorizer(t *testing.T) {
+	client, _ := NewAPIClient("key", "secret")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", client.FormatResource(PusherAuthEndpoint),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "key", req.Header.Get("X-SBTC-APIKEY"))
+			assert.NotEmpty(t, req.Header.Get("X-SBTC-SIGNATURE"))
+
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"socket_id": "123.456", "channel_name": "private-abc"}`, string(body))
+			return httpmock.NewStringResponse(200, `{"auth": "key:signature"}`), nil
+		})
+
+	auth, err := client.PusherAuthorizer()(context.Background(), "123.456", "private-abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "key:signature", auth)
+}
+
+func TestRealtime_SubscribeAccount(t *testing.T) {
+	standIn := newPusherStandIn()
+	defer standIn.server.Close()
+
+	authorize := func(ctx context.Context, socketID string, channel string) (string, error) {
+		return "key:" + socketID + ":" + channel, nil
+	}
+
+	realtime := NewRealtime(standIn.url())
+	assert.Error(t, realtime.SubscribeAccount(context.Background(), AccountChannel("abc"), nil))
+	assert.NoError(t, realtime.SubscribeAccount(context.Background(), AccountChannel("abc"), authorize))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go realtime.Run(ctx)
+
+	var feed AccountFeed = realtime
+	nextEvent(t, feed.Events())
+	subscription := nextSubscription(t, standIn)
+	assert.Equal(t, "private-abc", subscription["channel"])
+	assert.Equal(t, "key:123.456:private-abc", subscription["auth"])
+
+	standIn.publish("private-abc", OrderUpdatedEvent, map[string]interface{}{
+		"id": 7, "type": "Bid", "state": "traded", "market_id": "BTC-CLP",
+		"traded_amount": []string{"0.5", "BTC"},
+	})
+	order := nextEvent(t, feed.Events()).(*OrderUpdated)
+	assert.Equal(t, "private-abc", order.Channel())
+	assert.Equal(t, OrderStateTraded, order.Order.State)
+	assert.Equal(t, "0.5 BTC", order.Order.TradedAmount.String())
+
+	standIn.publish("private-abc", BalanceUpdatedEvent, map[string]interface{}{
+		"id": "CLP", "available_amount": []string{"1000.0", "CLP"},
+	})
+	balance := nextEvent(t, feed.Events()).(*BalanceUpdated)
+	assert.Equal(t, "CLP", balance.Balance.ID)
+
+	standIn.publish("private-abc", DepositUpdatedEvent, map[string]interface{}{
+		"id": 3, "state": "confirmed", "currency": "BTC",
+	})
+	deposit := nextEvent(t, feed.Events()).(*DepositUpdated)
+	assert.Equal(t, DepositStateConfirmed, deposit.Deposit.State)
+}
+
+func TestAccountPoller_EmitsChanges(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var mutex sync.Mutex
+	available := "10.0"
+	polls := 0
+
+	httpmock.RegisterResponder("GET", client.FormatResource(BalancesEndpoint),
+		func(req *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			polls++
+			body, _ := json.Marshal(map[string]interface{}{"balances": []interface{}{
+				map[string]interface{}{"id": "BTC", "available_amount": []string{available, "BTC"}},
+				map[string]interface{}{"id": "CLP", "available_amount": []string{"5.0", "CLP"}},
+			}})
+			return httpmock.NewBytesResponse(200, body), nil
+		})
+	mockResponseFromFile(client.FormatResource("/markets/BTC-CLP/orders"), "fixtures/orders.json")
+	mockResponseFromFile(client.FormatResource("/currencies/BTC/deposits"), "fixtures/deposits.json")
+
+	poller := client.NewAccountPoller(5*time.Millisecond, []string{"BTC-CLP"}, []string{"BTC"})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- poller.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return polls > 1
+	}, time.Second, time.Millisecond)
+
+	mutex.Lock()
+	available = "9.5"
+	mutex.Unlock()
+
+	balance := nextEvent(t, poller.Events()).(*BalanceUpdated)
+	assert.Equal(t, "BTC", balance.Balance.ID)
+	assert.Equal(t, "9.5 BTC", balance.Balance.AvailableAmount.String())
+
+	cancel()
+	for range poller.Events() {
+	}
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestAccountPoller_ReportsErrors(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(BalancesEndpoint),
+		httpmock.NewStringResponder(401, `{"message": "Invalid API key", "code": "unauthorized"}`))
+
+	poller := client.NewAccountPoller(0, nil, nil)
+	assert.Equal(t, DefaultPollInterval, poller.interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- poller.Run(ctx) }()
+
+	select {
+	case err := <-poller.Errors():
+		assert.True(t, IsUnauthorized(err))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for error")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	_, open := <-poller.Errors()
+	assert.False(t, open)
+	assert.Equal(t, ErrAlreadyRun, poller.Run(context.Background()))
+}
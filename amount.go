@@ -0,0 +1,117 @@
+package buda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrCurrencyMismatch = errors.New("amounts have different currencies")
+
+// Amount is an exact decimal value in a given currency, encoded by the API
+// as a ["value", "currency"] pair.
+type Amount struct {
+	Value    decimal.Decimal
+	Currency string
+}
+
+func NewAmount(value string, currency string) (Amount, error) {
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{Value: parsed, Currency: currency}, nil
+}
+
+func (amount Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string{amount.Value.String(), amount.Currency})
+}
+
+func (amount *Amount) UnmarshalJSON(data []byte) error {
+	var pair []string
+
+	if string(data) == "null" {
+		*amount = Amount{}
+		return nil
+	}
+
+	err := json.Unmarshal(data, &pair)
+	if err != nil {
+		return err
+	}
+
+	if len(pair) != 2 {
+		return fmt.Errorf("amount must be a [value, currency] pair, got %d elements", len(pair))
+	}
+
+	parsed, err := NewAmount(pair[0], pair[1])
+	if err != nil {
+		return err
+	}
+
+	*amount = parsed
+	return nil
+}
+
+func (amount Amount) String() string {
+	return amount.Value.String() + " " + amount.Currency
+}
+
+func (amount Amount) compatible(other Amount) error {
+	if amount.Currency != other.Currency {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+func (amount Amount) Add(other Amount) (Amount, error) {
+	if err := amount.compatible(other); err != nil {
+		return Amount{}, err
+	}
+	return Amount{Value: amount.Value.Add(other.Value), Currency: amount.Currency}, nil
+}
+
+func (amount Amount) Sub(other Amount) (Amount, error) {
+	if err := amount.compatible(other); err != nil {
+		return Amount{}, err
+	}
+	return Amount{Value: amount.Value.Sub(other.Value), Currency: amount.Currency}, nil
+}
+
+func (amount Amount) Mul(factor decimal.Decimal) Amount {
+	return Amount{Value: amount.Value.Mul(factor), Currency: amount.Currency}
+}
+
+func (amount Amount) Neg() Amount {
+	return Amount{Value: amount.Value.Neg(), Currency: amount.Currency}
+}
+
+func (amount Amount) Cmp(other Amount) (int, error) {
+	if err := amount.compatible(other); err != nil {
+		return 0, err
+	}
+	return amount.Value.Cmp(other.Value), nil
+}
+
+func (amount Amount) Equal(other Amount) bool {
+	return amount.Currency == other.Currency && amount.Value.Equal(other.Value)
+}
+
+func (amount Amount) IsZero() bool {
+	return amount.Value.IsZero()
+}
+
+func (amount Amount) IsNegative() bool {
+	return amount.Value.IsNegative()
+}
+
+func (amount Amount) Float64() float64 {
+	value, _ := amount.Value.Float64()
+	return value
+}
+
+func (amount Amount) StringFixed(places int32) string {
+	return amount.Value.StringFixed(places) + " " + amount.Currency
+}
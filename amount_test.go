@@ -0,0 +1,62 @@
+package buda
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmount_UnmarshalJSON(t *testing.T) {
+	var amount Amount
+	err := json.Unmarshal([]byte(`["0.1", "BTC"]`), &amount)
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", amount.Currency)
+	assert.True(t, amount.Value.Equal(decimal.RequireFromString("0.1")))
+
+	err = json.Unmarshal([]byte(`null`), &amount)
+	assert.NoError(t, err)
+	assert.True(t, amount.IsZero())
+
+	assert.Error(t, json.Unmarshal([]byte(`["0.1"]`), &amount))
+	assert.Error(t, json.Unmarshal([]byte(`["abc", "BTC"]`), &amount))
+}
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	amount, _ := NewAmount("1728000.0", "CLP")
+	data, err := json.Marshal(amount)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["1728000", "CLP"]`, string(data))
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	a, _ := NewAmount("0.1", "BTC")
+	b, _ := NewAmount("0.2", "BTC")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3 BTC", sum.String())
+
+	diff, err := a.Sub(b)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsNegative())
+	assert.Equal(t, "0.1 BTC", diff.Neg().String())
+
+	cmp, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	assert.Equal(t, "0.30 BTC", a.Mul(decimal.NewFromInt(3)).StringFixed(2))
+}
+
+func TestAmount_CurrencyMismatch(t *testing.T) {
+	a, _ := NewAmount("1", "BTC")
+	b, _ := NewAmount("1", "CLP")
+
+	_, err := a.Add(b)
+	assert.Equal(t, ErrCurrencyMismatch, err)
+	_, err = a.Cmp(b)
+	assert.Equal(t, ErrCurrencyMismatch, err)
+	assert.False(t, a.Equal(b))
+}
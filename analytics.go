@@ -0,0 +1,217 @@
+package buda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrEmptyBook             = errors.New("order book side is empty")
+	ErrInsufficientLiquidity = errors.New("not enough liquidity in the order book")
+)
+
+var basisPoints = decimal.NewFromInt(10000)
+
+// sortedLevels parses one side of the book, best price first.
+func (orderBook *OrderBook) sortedLevels(side BookSide) ([]PriceLevel, error) {
+	entries := orderBook.Asks
+	if side == BookSideBids {
+		entries = orderBook.Bids
+	}
+
+	levels, err := parseLevels(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(levels, func(i, j int) bool {
+		if side == BookSideBids {
+			return levels[i].Price.GreaterThan(levels[j].Price)
+		}
+		return levels[i].Price.LessThan(levels[j].Price)
+	})
+
+	return levels, nil
+}
+
+func (orderBook *OrderBook) best(side BookSide) (PriceLevel, error) {
+	levels, err := orderBook.sortedLevels(side)
+	if err != nil {
+		return PriceLevel{}, err
+	}
+	if len(levels) == 0 {
+		return PriceLevel{}, ErrEmptyBook
+	}
+	return levels[0], nil
+}
+
+func (orderBook *OrderBook) BestBid() (PriceLevel, error) {
+	return orderBook.best(BookSideBids)
+}
+
+func (orderBook *OrderBook) BestAsk() (PriceLevel, error) {
+	return orderBook.best(BookSideAsks)
+}
+
+func (orderBook *OrderBook) Mid() (decimal.Decimal, error) {
+	bid, err := orderBook.BestBid()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	ask, err := orderBook.BestAsk()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), nil
+}
+
+// Spread is the best ask minus the best bid, in quote currency.
+func (orderBook *OrderBook) Spread() (decimal.Decimal, error) {
+	bid, err := orderBook.BestBid()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	ask, err := orderBook.BestAsk()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return ask.Price.Sub(bid.Price), nil
+}
+
+// DepthWithin returns the base amount resting on each side at prices within
+// bps basis points of the mid price.
+func (orderBook *OrderBook) DepthWithin(bps int64) (bids decimal.Decimal, asks decimal.Decimal, err error) {
+	mid, err := orderBook.Mid()
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	offset := mid.Mul(decimal.NewFromInt(bps)).Div(basisPoints)
+
+	bidLevels, err := orderBook.sortedLevels(BookSideBids)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	floor := mid.Sub(offset)
+	for _, level := range bidLevels {
+		if level.Price.LessThan(floor) {
+			break
+		}
+		bids = bids.Add(level.Amount)
+	}
+
+	askLevels, err := orderBook.sortedLevels(BookSideAsks)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	ceiling := mid.Add(offset)
+	for _, level := range askLevels {
+		if level.Price.GreaterThan(ceiling) {
+			break
+		}
+		asks = asks.Add(level.Amount)
+	}
+
+	return bids, asks, nil
+}
+
+// takerSide is the side of the book an order of the given type consumes:
+// bids buy from the asks and asks sell into the bids.
+func takerSide(orderType OrderType) (BookSide, error) {
+	switch orderType {
+	case OrderTypeBid:
+		return BookSideAsks, nil
+	case OrderTypeAsk:
+		return BookSideBids, nil
+	}
+	return "", fmt.Errorf("invalid order type %q", orderType)
+}
+
+// fill walks the book for an order of the given type until limit is reached,
+// measured in base amount or, with quote set, in quote value. It returns the
+// base amount filled and the quote value exchanged.
+func (orderBook *OrderBook) fill(orderType OrderType, limit decimal.Decimal, quote bool) (decimal.Decimal, decimal.Decimal, error) {
+	if !limit.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("amount must be positive, got %s", limit)
+	}
+
+	side, err := takerSide(orderType)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	levels, err := orderBook.sortedLevels(side)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	filled, exchanged := decimal.Zero, decimal.Zero
+	for _, level := range levels {
+		amount := level.Amount
+		value := level.Price.Mul(amount)
+
+		if quote {
+			if remaining := limit.Sub(exchanged); value.GreaterThanOrEqual(remaining) {
+				return filled.Add(remaining.Div(level.Price)), limit, nil
+			}
+		} else {
+			if remaining := limit.Sub(filled); amount.GreaterThanOrEqual(remaining) {
+				return limit, exchanged.Add(level.Price.Mul(remaining)), nil
+			}
+		}
+
+		filled = filled.Add(amount)
+		exchanged = exchanged.Add(value)
+	}
+
+	return filled, exchanged, ErrInsufficientLiquidity
+}
+
+// VWAPForAmount is the average price an order of the given type would get for
+// amount units of the base currency.
+func (orderBook *OrderBook) VWAPForAmount(orderType OrderType, amount decimal.Decimal) (decimal.Decimal, error) {
+	filled, exchanged, err := orderBook.fill(orderType, amount, false)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return exchanged.Div(filled), nil
+}
+
+// VWAPForQuoteValue is the average price an order of the given type would get
+// when exchanging value units of the quote currency.
+func (orderBook *OrderBook) VWAPForQuoteValue(orderType OrderType, value decimal.Decimal) (decimal.Decimal, error) {
+	filled, exchanged, err := orderBook.fill(orderType, value, true)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return exchanged.Div(filled), nil
+}
+
+// SlippageFor is how much worse than the best price, in basis points, the
+// average price of an order for amount units of the base currency would be.
+func (orderBook *OrderBook) SlippageFor(orderType OrderType, amount decimal.Decimal) (decimal.Decimal, error) {
+	vwap, err := orderBook.VWAPForAmount(orderType, amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	side, _ := takerSide(orderType)
+	best, err := orderBook.best(side)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	difference := vwap.Sub(best.Price)
+	if orderType == OrderTypeAsk {
+		difference = difference.Neg()
+	}
+
+	return difference.Mul(basisPoints).Div(best.Price), nil
+}
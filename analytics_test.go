@@ -0,0 +1,102 @@
+package buda
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBook() *OrderBook {
+	return &OrderBook{
+		Bids: [][]string{{"100", "1"}, {"99", "2"}},
+		Asks: [][]string{{"103", "2"}, {"101", "1"}},
+	}
+}
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	assert.True(t, decimal.RequireFromString(expected).Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+func TestOrderBook_TopOfBook(t *testing.T) {
+	book := testBook()
+
+	bid, err := book.BestBid()
+	assert.NoError(t, err)
+	assertDecimal(t, "100", bid.Price)
+
+	ask, err := book.BestAsk()
+	assert.NoError(t, err)
+	assertDecimal(t, "101", ask.Price)
+
+	mid, err := book.Mid()
+	assert.NoError(t, err)
+	assertDecimal(t, "100.5", mid)
+
+	spread, err := book.Spread()
+	assert.NoError(t, err)
+	assertDecimal(t, "1", spread)
+
+	_, err = (&OrderBook{Bids: book.Bids}).Mid()
+	assert.Equal(t, ErrEmptyBook, err)
+}
+
+func TestOrderBook_DepthWithin(t *testing.T) {
+	book := testBook()
+
+	bids, asks, err := book.DepthWithin(100)
+	assert.NoError(t, err)
+	assertDecimal(t, "1", bids)
+	assertDecimal(t, "1", asks)
+
+	bids, asks, err = book.DepthWithin(300)
+	assert.NoError(t, err)
+	assertDecimal(t, "3", bids)
+	assertDecimal(t, "3", asks)
+}
+
+func TestOrderBook_VWAPAndSlippage(t *testing.T) {
+	book := testBook()
+
+	vwap, err := book.VWAPForAmount(OrderTypeBid, decimal.NewFromInt(2))
+	assert.NoError(t, err)
+	assertDecimal(t, "102", vwap)
+
+	vwap, err = book.VWAPForAmount(OrderTypeAsk, decimal.RequireFromString("0.5"))
+	assert.NoError(t, err)
+	assertDecimal(t, "100", vwap)
+
+	vwap, err = book.VWAPForQuoteValue(OrderTypeBid, decimal.NewFromInt(204))
+	assert.NoError(t, err)
+	assertDecimal(t, "102", vwap)
+
+	slippage, err := book.SlippageFor(OrderTypeAsk, decimal.NewFromInt(2))
+	assert.NoError(t, err)
+	assertDecimal(t, "50", slippage)
+
+	slippage, err = book.SlippageFor(OrderTypeBid, decimal.NewFromInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, "99.01", slippage.StringFixed(2))
+
+	_, err = book.VWAPForAmount(OrderTypeBid, decimal.NewFromInt(4))
+	assert.Equal(t, ErrInsufficientLiquidity, err)
+
+	_, err = book.VWAPForAmount(OrderTypeBid, decimal.Zero)
+	assert.Error(t, err)
+}
+
+func TestOrderBook_RejectsInvalidLevels(t *testing.T) {
+	book := &OrderBook{
+		Bids: [][]string{{"100", "1"}},
+		Asks: [][]string{{"0", "1"}, {"101", "1"}},
+	}
+
+	_, err := book.SlippageFor(OrderTypeBid, decimal.NewFromInt(1))
+	assert.Error(t, err)
+
+	_, err = book.VWAPForQuoteValue(OrderTypeBid, decimal.NewFromInt(50))
+	assert.Error(t, err)
+
+	_, err = (&OrderBook{Bids: [][]string{{"100", "-1"}}}).BestBid()
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package buda
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,7 +13,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"strconv"
+	"sync"
+	"io"
 	"io/ioutil"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -20,8 +27,11 @@ const (
 	MarketEndpoint = "/markets/%d"
 	MarketVolumeEndpoint = "/markets/%s/volume"
 	MarketTickerEndpoint = "/markets/%s/ticker"
+	TickersEndpoint = "/tickers"
 	MarketOrderBookEndpoint = "/markets/%s/order_book"
 	MarketTradesEndpoint = "/markets/%s/trades"
+	MarketQuotationsEndpoint = "/markets/%s/quotations"
+	MarketReportsEndpoint = "/markets/%s/reports"
 	BalancesEndpoint = "/balances"
 	OrdersEndpoint = "/markets/%s/orders"
 	OrderEndpoint = "/orders/%d"
@@ -29,22 +39,40 @@ const (
 	DepositsEndpoint = "/currencies/%s/deposits"
 	DepositFeeEndpoint = "/currencies/%s/fees/deposit"
 	WithdrawalFeeEndpoint = "/currencies/%s/fees/withdrawal"
-	ReceiveAddressEndpoint = "/currencies/%s/receive_addresses/%s"
+	ReceiveAddressesEndpoint = "/currencies/%s/receive_addresses"
+	ReceiveAddressEndpoint = "/currencies/%s/receive_addresses/%d"
 	ElementsPerPage = "300"
+	PageConcurrency = 4
+	CancelConcurrency = 5
+	TickerConcurrency = 5
+)
+
+const (
+	QuotationBidGivenSize = "bid_given_size"
+	QuotationAskGivenSize = "ask_given_size"
+	QuotationBidGivenValue = "bid_given_value"
+	QuotationAskGivenValue = "ask_given_value"
 )
 
 type APIClient struct {
 	Key string
 	Secret string
 	Client *http.Client
+	PublicLimiter *rate.Limiter
+	PrivateLimiter *rate.Limiter
+	Retry *RetryPolicy
+	Nonce NonceSource
+	BaseURL string
+	UserAgent string
+	timeout time.Duration
 }
 
 type Market struct {
-	ID                 string   `json:"id"`
-	Name               string   `json:"name"`
-	BaseCurrency       string   `json:"base_currency"`
-	QuoteCurrency      string   `json:"quote_currency"`
-	MinimumOrderAmount []string `json:"minimum_order_amount"`
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	BaseCurrency       string `json:"base_currency"`
+	QuoteCurrency      string `json:"quote_currency"`
+	MinimumOrderAmount Amount `json:"minimum_order_amount"`
 }
 
 type Markets struct {
@@ -56,9 +84,9 @@ type MarketSingle struct {
 }
 
 type Fee struct {
-	Name string `json:"name"`
+	Name    string  `json:"name"`
 	Percent float64 `json:"percent"`
-	Base []string `json:"base"`
+	Base    Amount  `json:"base"`
 }
 
 type FeeSingle struct {
@@ -66,11 +94,11 @@ type FeeSingle struct {
 }
 
 type Volume struct {
-	AskVolume24H []string `json:"ask_volume_24h"`
-	AskVolume7D  []string `json:"ask_volume_7d"`
-	BidVolume24H []string `json:"bid_volume_24h"`
-	BidVolume7D  []string `json:"bid_volume_7d"`
-	MarketID     string   `json:"market_id"`
+	AskVolume24H Amount `json:"ask_volume_24h"`
+	AskVolume7D  Amount `json:"ask_volume_7d"`
+	BidVolume24H Amount `json:"bid_volume_24h"`
+	BidVolume7D  Amount `json:"bid_volume_7d"`
+	MarketID     string `json:"market_id"`
 }
 
 type VolumeSingle struct {
@@ -78,18 +106,23 @@ type VolumeSingle struct {
 }
 
 type Ticker struct {
-	LastPrice         []string `json:"last_price"`
-	MaxBid            []string `json:"max_bid"`
-	MinAsk            []string `json:"min_ask"`
+	MarketID          string `json:"market_id"`
+	LastPrice         Amount `json:"last_price"`
+	MaxBid            Amount `json:"max_bid"`
+	MinAsk            Amount `json:"min_ask"`
 	PriceVariation24H string `json:"price_variation_24h"`
 	PriceVariation7D  string `json:"price_variation_7d"`
-	Volume            []string `json:"volume"`
+	Volume            Amount `json:"volume"`
 }
 
 type TickerSingle struct {
 	Ticker Ticker `json:"ticker"`
 }
 
+type Tickers struct {
+	Tickers []Ticker `json:"tickers"`
+}
+
 type OrderBook struct {
 	Asks [][]string `json:"asks"`
 	Bids [][]string `json:"bids"`
@@ -99,11 +132,56 @@ type OrderBookSingle struct {
 	OrderBook OrderBook `json:"order_book"`
 }
 
+type QuotationRequest struct {
+	Type   string          `json:"type"`
+	Amount decimal.Decimal `json:"amount"`
+	Limit  decimal.Decimal `json:"limit,omitempty"`
+}
+
+type Quotation struct {
+	Type               string `json:"type"`
+	Amount             Amount `json:"amount"`
+	Limit              Amount `json:"limit"`
+	BaseBalanceChange  Amount `json:"base_balance_change"`
+	QuoteBalanceChange Amount `json:"quote_balance_change"`
+	BaseExchanged      Amount `json:"base_exchanged"`
+	QuoteExchanged     Amount `json:"quote_exchanged"`
+	Fee                Amount `json:"fee"`
+	OrderAmount        Amount `json:"order_amount"`
+	Incomplete         bool   `json:"incomplete"`
+}
+
+type QuotationSingle struct {
+	Quotation Quotation `json:"quotation"`
+}
+
+type AveragePrice struct {
+	Timestamp time.Time
+	Average   decimal.Decimal
+}
+
+type Candle struct {
+	Timestamp time.Time
+	Open      decimal.Decimal
+	Close     decimal.Decimal
+	High      decimal.Decimal
+	Low       decimal.Decimal
+	Volume    decimal.Decimal
+}
+
+type AveragePriceReports struct {
+	Reports []AveragePrice `json:"reports"`
+}
+
+type CandleReports struct {
+	Reports []Candle `json:"reports"`
+}
+
 type Trade struct {
-	MarketId 	  string	 `json:"market_id"`
-	Timestamp     string	 `json:"timestamp"`
-	LastTimestamp string     `json:"last_timestamp"`
-	Entries       [][]interface{} `json:"entries"`
+	MarketId      string       `json:"market_id"`
+	Timestamp     time.Time    `json:"timestamp"`
+	LastTimestamp time.Time    `json:"last_timestamp"`
+	Entries       []TradeEntry `json:"entries"`
 }
 
 type Trades struct {
@@ -111,12 +189,12 @@ type Trades struct {
 }
 
 type Balance struct {
-	ID                    string   `json:"id"`
-	Amount                []string `json:"amount"`
-	AvailableAmount       []string `json:"available_amount"`
-	FrozenAmount          []string `json:"frozen_amount"`
-	PendingWithdrawAmount []string `json:"pending_withdraw_amount"`
-	AccountID             int      `json:"account_id"`
+	ID                    string `json:"id"`
+	Amount                Amount `json:"amount"`
+	AvailableAmount       Amount `json:"available_amount"`
+	FrozenAmount          Amount `json:"frozen_amount"`
+	PendingWithdrawAmount Amount `json:"pending_withdraw_amount"`
+	AccountID             int    `json:"account_id"`
 }
 
 type Balances struct {
@@ -134,20 +212,37 @@ type Metadata struct {
 }
 
 type Order struct {
-	ID             int       `json:"id"`
-	Type           string    `json:"type"`
-	State          string    `json:"state"`
-	CreatedAt      time.Time `json:"created_at"`
-	MarketID       string    `json:"market_id"`
-	AccountID      int       `json:"account_id"`
-	FeeCurrency    string    `json:"fee_currency"`
-	PriceType      string    `json:"price_type"`
-	Limit          []string  `json:"limit"`
-	Amount         []string  `json:"amount"`
-	OriginalAmount []string  `json:"original_amount"`
-	TradedAmount   []string  `json:"traded_amount"`
-	TotalExchanged []string  `json:"total_exchanged"`
-	PaidFee        []string  `json:"paid_fee"`
+	ID             int        `json:"id"`
+	Type           OrderType  `json:"type"`
+	State          OrderState `json:"state"`
+	CreatedAt      time.Time  `json:"created_at"`
+	MarketID       string     `json:"market_id"`
+	AccountID      int        `json:"account_id"`
+	FeeCurrency    string     `json:"fee_currency"`
+	PriceType      PriceType  `json:"price_type"`
+	Limit          Amount     `json:"limit"`
+	Amount         Amount     `json:"amount"`
+	OriginalAmount Amount     `json:"original_amount"`
+	TradedAmount   Amount     `json:"traded_amount"`
+	TotalExchanged Amount     `json:"total_exchanged"`
+	PaidFee        Amount     `json:"paid_fee"`
+}
+
+type OrderRequest struct {
+	Type      OrderType       `json:"type"`
+	PriceType PriceType       `json:"price_type"`
+	Limit     decimal.Decimal `json:"limit,omitempty"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+type OrderStateRequest struct {
+	State OrderState `json:"state"`
+}
+
+type CancelResult struct {
+	ID    int
+	Order *Order
+	Err   error
 }
 
 type OrderSingle struct {
@@ -166,13 +261,13 @@ type DepositData struct {
 }
 
 type Deposit struct {
-	ID          int      `json:"id"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-	Amount      []string `json:"amount"`
-	Currency    string   `json:"currency"`
-	State       string   `json:"state"`
-	DepositData DepositData `json:"deposit_data"`
+	ID          int          `json:"id"`
+	CreatedAt   string       `json:"created_at"`
+	UpdatedAt   string       `json:"updated_at"`
+	Amount      Amount       `json:"amount"`
+	Currency    string       `json:"currency"`
+	State       DepositState `json:"state"`
+	DepositData DepositData  `json:"deposit_data"`
 }
 
 type Deposits struct {
@@ -187,14 +282,24 @@ type WithdrawalData struct {
 }
 
 type Withdrawal struct {
-	ID        int    `json:"id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
-	State          string   `json:"state"`
-	Amount         []string `json:"amount"`
-	Fee            []string `json:"fee"`
-	Currency       string   `json:"currency"`
-	WithdrawalData WithdrawalData `json:"withdrawal_data"`
+	ID             int             `json:"id"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+	State          WithdrawalState `json:"state"`
+	Amount         Amount          `json:"amount"`
+	Fee            Amount          `json:"fee"`
+	Currency       string          `json:"currency"`
+	WithdrawalData WithdrawalData  `json:"withdrawal_data"`
+}
+
+type WithdrawalTarget struct {
+	TargetAddress string `json:"target_address"`
+}
+
+type WithdrawalRequest struct {
+	Amount         decimal.Decimal  `json:"amount"`
+	WithdrawalData WithdrawalTarget `json:"withdrawal_data"`
+	Simulate       bool             `json:"simulate,omitempty"`
 }
 
 type WithdrawalSingle struct {
@@ -207,11 +312,15 @@ type Withdrawals struct {
 }
 
 type ReceiveAddress struct {
-	ID        int    `json:"id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
-	Address   string `json:"address"`
-	Used      bool   `json:"used"`
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Address   string    `json:"address"`
+	Used      bool      `json:"used"`
+}
+
+type ReceiveAddresses struct {
+	ReceiveAddresses []ReceiveAddress `json:"receive_addresses"`
 }
 
 type ReceiveAddressSingle struct {
@@ -226,20 +335,25 @@ func (client *APIClient) SignRequest(params...string) (string) {
 
 func (client *APIClient) AuthenticatedRequest(request *http.Request) (*http.Request, error) {
 	var signature string
-	timestamp := strconv.FormatInt(time.Now().UTC().UnixNano()*1E6, 10)
+	nonce := client.Nonce
+	if nonce == nil {
+		nonce = defaultNonce
+	}
+	timestamp := nonce.Nonce()
 
-	switch request.Method {
-		case "POST": {
-			var body []byte
-			body, err := ioutil.ReadAll(request.Body)
-			if err != nil {
-				return nil, err
-			}
-			signature = client.SignRequest(request.Method, request.URL.RequestURI(), base64.StdEncoding.EncodeToString(body), timestamp)
+	if request.Body != nil && request.Body != http.NoBody {
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
 		}
-		case "GET": {
-			signature = client.SignRequest(request.Method, request.URL.RequestURI(), timestamp)
+		request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		request.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
 		}
+		signature = client.SignRequest(request.Method, request.URL.RequestURI(), base64.StdEncoding.EncodeToString(body), timestamp)
+	} else {
+		signature = client.SignRequest(request.Method, request.URL.RequestURI(), timestamp)
 	}
 
 	request.Header.Set("X-SBTC-APIKEY", client.Key)
@@ -249,16 +363,86 @@ func (client *APIClient) AuthenticatedRequest(request *http.Request) (*http.Requ
 	return request, nil
 }
 
-func NewAPIClient(apiKey string, apiSecret string) (*APIClient, error){
- 	return &APIClient{Client: &http.Client{}, Key: apiKey, Secret: apiSecret}, nil
+func NewAPIClient(apiKey string, apiSecret string, options ...Option) (*APIClient, error){
+	retry := DefaultRetryPolicy
+ 	client := &APIClient{
+		Client: &http.Client{},
+		Key: apiKey,
+		Secret: apiSecret,
+		PublicLimiter: rate.NewLimiter(DefaultPublicRate, DefaultPublicBurst),
+		PrivateLimiter: rate.NewLimiter(DefaultPrivateRate, DefaultPrivateBurst),
+		Retry: &retry,
+		Nonce: defaultNonce,
+		BaseURL: BaseURL,
+		UserAgent: DefaultUserAgent,
+	}
+
+	for _, option := range options {
+		err := option(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if client.timeout > 0 {
+		httpClient := *client.Client
+		httpClient.Timeout = client.timeout
+		client.Client = &httpClient
+	}
+
+	return client, nil
 }
 
 func (client *APIClient) FormatResource(resource string) (string) {
-	return fmt.Sprintf("%s%s", BaseURL, resource)
+	baseURL := client.BaseURL
+	if baseURL == "" {
+		baseURL = BaseURL
+	}
+	return fmt.Sprintf("%s%s", baseURL, resource)
 }
 
-func (client *APIClient) Get(resource string, private bool) ([]byte, error) {
-	req, err := http.NewRequest("GET", client.FormatResource(resource), nil)
+func (client *APIClient) request(ctx context.Context, method string, resource string, payload []byte, private bool) ([]byte, error) {
+	attempts := client.Retry.attempts(ctx, method)
+
+	for attempt := 1; ; attempt++ {
+		body, err := client.do(ctx, method, resource, payload, private)
+		if err == nil || attempt >= attempts {
+			return body, err
+		}
+
+		retry, retryAfter := retryable(ctx, err)
+		if !retry {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(client.Retry.backoff(attempt, retryAfter)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (client *APIClient) do(ctx context.Context, method string, resource string, payload []byte, private bool) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, client.FormatResource(resource), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client.UserAgent != "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
+
+	err = client.wait(ctx, private)
 	if err != nil {
 		return nil, err
 	}
@@ -274,19 +458,74 @@ func (client *APIClient) Get(resource string, private bool) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer response.Body.Close()
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return nil, err
 	}
 
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, newAPIError(response, method, resource, body)
+	}
+
 	return body, nil
 }
 
+func (client *APIClient) send(ctx context.Context, method string, resource string, payload interface{}, private bool) ([]byte, error) {
+	var body []byte
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	return client.request(ctx, method, resource, body, private)
+}
+
+func (client *APIClient) Get(resource string, private bool) ([]byte, error) {
+	return client.GetWithContext(context.Background(), resource, private)
+}
+
+func (client *APIClient) GetWithContext(ctx context.Context, resource string, private bool) ([]byte, error) {
+	return client.request(ctx, "GET", resource, nil, private)
+}
+
+func (client *APIClient) Post(resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.PostWithContext(context.Background(), resource, payload, private)
+}
+
+func (client *APIClient) PostWithContext(ctx context.Context, resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.send(ctx, "POST", resource, payload, private)
+}
+
+func (client *APIClient) Put(resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.PutWithContext(context.Background(), resource, payload, private)
+}
+
+func (client *APIClient) PutWithContext(ctx context.Context, resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.send(ctx, "PUT", resource, payload, private)
+}
+
+func (client *APIClient) Delete(resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.DeleteWithContext(context.Background(), resource, payload, private)
+}
+
+func (client *APIClient) DeleteWithContext(ctx context.Context, resource string, payload interface{}, private bool) ([]byte, error) {
+	return client.send(ctx, "DELETE", resource, payload, private)
+}
+
 func (client *APIClient) GetMarkets() ([]Market, error) {
+	return client.GetMarketsWithContext(context.Background())
+}
+
+func (client *APIClient) GetMarketsWithContext(ctx context.Context) ([]Market, error) {
 	var markets Markets
 
-	response, err := client.Get(MarketsEndpoint, false)
+	response, err := client.GetWithContext(ctx, MarketsEndpoint, false)
 	if err != nil {
 		return nil, err
 	}
@@ -300,9 +539,13 @@ func (client *APIClient) GetMarkets() ([]Market, error) {
 }
 
 func (client *APIClient) GetMarket(id int) (*Market, error) {
+	return client.GetMarketWithContext(context.Background(), id)
+}
+
+func (client *APIClient) GetMarketWithContext(ctx context.Context, id int) (*Market, error) {
 	var market MarketSingle
 
-	data, err := client.Get(fmt.Sprintf(MarketEndpoint, id), false)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(MarketEndpoint, id), false)
 	if err != nil {
 		return nil, err
 	}
@@ -316,9 +559,13 @@ func (client *APIClient) GetMarket(id int) (*Market, error) {
 }
 
 func (client *APIClient) GetVolumeByMarket(marketId string) (*Volume, error) {
+	return client.GetVolumeByMarketWithContext(context.Background(), marketId)
+}
+
+func (client *APIClient) GetVolumeByMarketWithContext(ctx context.Context, marketId string) (*Volume, error) {
 	var volume VolumeSingle
 
-	data, err := client.Get(fmt.Sprintf(MarketVolumeEndpoint, marketId),false)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(MarketVolumeEndpoint, marketId),false)
 	if err != nil {
 		return nil, err
 	}
@@ -332,9 +579,13 @@ func (client *APIClient) GetVolumeByMarket(marketId string) (*Volume, error) {
 }
 
 func (client *APIClient) GetTickerByMarket(marketId string) (*Ticker, error) {
+	return client.GetTickerByMarketWithContext(context.Background(), marketId)
+}
+
+func (client *APIClient) GetTickerByMarketWithContext(ctx context.Context, marketId string) (*Ticker, error) {
 	var ticker TickerSingle
 
-	data, err := client.Get(fmt.Sprintf(MarketTickerEndpoint, marketId),false)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(MarketTickerEndpoint, marketId),false)
 	if err != nil {
 		return nil, err
 	}
@@ -344,13 +595,108 @@ func (client *APIClient) GetTickerByMarket(marketId string) (*Ticker, error) {
 		return nil, err
 	}
 
+	if ticker.Ticker.MarketID == "" {
+		ticker.Ticker.MarketID = marketId
+	}
+
 	return &ticker.Ticker, nil
 }
 
+func (client *APIClient) GetAllTickers() (map[string]Ticker, error) {
+	return client.GetAllTickersWithContext(context.Background())
+}
+
+// GetAllTickersWithContext uses the tickers endpoint and, where it is not
+// available, fetches the ticker of every market with at most
+// TickerConcurrency requests in flight.
+func (client *APIClient) GetAllTickersWithContext(ctx context.Context) (map[string]Ticker, error) {
+	var tickers Tickers
+
+	data, err := client.GetWithContext(ctx, TickersEndpoint, false)
+	if IsNotFound(err) {
+		return client.fanOutTickers(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Ticker, len(tickers.Tickers))
+	for _, ticker := range tickers.Tickers {
+		result[ticker.MarketID] = ticker
+	}
+
+	return result, nil
+}
+
+func (client *APIClient) fanOutTickers(ctx context.Context) (map[string]Ticker, error) {
+	markets, err := client.GetMarketsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tickers := make([]*Ticker, len(markets))
+	sem := make(chan struct{}, TickerConcurrency)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i, market := range markets {
+		wg.Add(1)
+		go func(i int, marketId string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				fail(ctx.Err())
+				return
+			}
+			ticker, err := client.GetTickerByMarketWithContext(ctx, marketId)
+			if err != nil {
+				fail(err)
+				return
+			}
+			tickers[i] = ticker
+		}(i, market.ID)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := make(map[string]Ticker, len(markets))
+	for i, market := range markets {
+		result[market.ID] = *tickers[i]
+	}
+
+	return result, nil
+}
+
 func (client *APIClient) GetOrderBookByMarket(marketId string) (*OrderBook, error) {
+	return client.GetOrderBookByMarketWithContext(context.Background(), marketId)
+}
+
+func (client *APIClient) GetOrderBookByMarketWithContext(ctx context.Context, marketId string) (*OrderBook, error) {
 	var orderBook OrderBookSingle
 
-	data, err := client.Get(fmt.Sprintf(MarketOrderBookEndpoint, marketId),false)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(MarketOrderBookEndpoint, marketId),false)
 	if err != nil {
 		return nil, err
 	}
@@ -363,7 +709,162 @@ func (client *APIClient) GetOrderBookByMarket(marketId string) (*OrderBook, erro
 	return &orderBook.OrderBook, nil
 }
 
+func (client *APIClient) GetQuotation(marketId string, quotation *QuotationRequest) (*Quotation, error) {
+	return client.GetQuotationWithContext(context.Background(), marketId, quotation)
+}
+
+func (client *APIClient) GetQuotationWithContext(ctx context.Context, marketId string, quotation *QuotationRequest) (*Quotation, error) {
+	var quote QuotationSingle
+
+	switch quotation.Type {
+	case QuotationBidGivenSize, QuotationAskGivenSize, QuotationBidGivenValue, QuotationAskGivenValue:
+	default:
+		return nil, fmt.Errorf("unknown quotation type: %q", quotation.Type)
+	}
+
+	data, err := client.PostWithContext(Idempotent(ctx), fmt.Sprintf(MarketQuotationsEndpoint, marketId), quotation, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quote.Quotation, nil
+}
+
+func parseReportEntry(data []byte, timestamp *time.Time, values ...*decimal.Decimal) error {
+	var fields []json.RawMessage
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) < len(values)+1 {
+		return fmt.Errorf("report entry has %d fields, expected %d", len(fields), len(values)+1)
+	}
+
+	var seconds json.Number
+	err = json.Unmarshal(fields[0], &seconds)
+	if err != nil {
+		return err
+	}
+
+	unix, err := seconds.Float64()
+	if err != nil {
+		return err
+	}
+	*timestamp = time.Unix(int64(unix), 0).UTC()
+
+	for i, value := range values {
+		*value, err = decimal.NewFromString(string(bytes.Trim(fields[i+1], `"`)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (price *AveragePrice) UnmarshalJSON(data []byte) error {
+	return parseReportEntry(data, &price.Timestamp, &price.Average)
+}
+
+func (candle *Candle) UnmarshalJSON(data []byte) error {
+	return parseReportEntry(data, &candle.Timestamp, &candle.Open, &candle.Close, &candle.High, &candle.Low, &candle.Volume)
+}
+
+// Request amounts go out as plain JSON numbers, written from the exact
+// decimal, and optional ones are left out when zero.
+func optionalNumber(value decimal.Decimal) json.Number {
+	if value.IsZero() {
+		return ""
+	}
+	return json.Number(value.String())
+}
+
+func (order OrderRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      OrderType   `json:"type"`
+		PriceType PriceType   `json:"price_type"`
+		Limit     json.Number `json:"limit,omitempty"`
+		Amount    json.Number `json:"amount"`
+	}{order.Type, order.PriceType, optionalNumber(order.Limit), json.Number(order.Amount.String())})
+}
+
+func (quotation QuotationRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string      `json:"type"`
+		Amount json.Number `json:"amount"`
+		Limit  json.Number `json:"limit,omitempty"`
+	}{quotation.Type, json.Number(quotation.Amount.String()), optionalNumber(quotation.Limit)})
+}
+
+func (withdrawal WithdrawalRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount         json.Number      `json:"amount"`
+		WithdrawalData WithdrawalTarget `json:"withdrawal_data"`
+		Simulate       bool             `json:"simulate,omitempty"`
+	}{json.Number(withdrawal.Amount.String()), withdrawal.WithdrawalData, withdrawal.Simulate})
+}
+
+func (client *APIClient) getReport(ctx context.Context, marketId string, reportType string, from time.Time, to time.Time, reports interface{}) error {
+	url := fmt.Sprintf(MarketReportsEndpoint, marketId) + "?report_type=" + reportType
+
+	if !from.IsZero() {
+		url += "&from=" + strconv.FormatInt(from.Unix(), 10)
+	}
+
+	if !to.IsZero() {
+		url += "&to=" + strconv.FormatInt(to.Unix(), 10)
+	}
+
+	data, err := client.GetWithContext(ctx, url, false)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, reports)
+}
+
+func (client *APIClient) GetAveragePrices(marketId string, from time.Time, to time.Time) ([]AveragePrice, error) {
+	return client.GetAveragePricesWithContext(context.Background(), marketId, from, to)
+}
+
+func (client *APIClient) GetAveragePricesWithContext(ctx context.Context, marketId string, from time.Time, to time.Time) ([]AveragePrice, error) {
+	var reports AveragePriceReports
+
+	err := client.getReport(ctx, marketId, "average_prices", from, to, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports.Reports, nil
+}
+
+func (client *APIClient) GetCandles(marketId string, from time.Time, to time.Time) ([]Candle, error) {
+	return client.GetCandlesWithContext(context.Background(), marketId, from, to)
+}
+
+func (client *APIClient) GetCandlesWithContext(ctx context.Context, marketId string, from time.Time, to time.Time) ([]Candle, error) {
+	var reports CandleReports
+
+	err := client.getReport(ctx, marketId, "candlestick", from, to, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports.Reports, nil
+}
+
 func (client *APIClient) GetTradesByMarket(marketId string, timestamp string) (*Trade, error) {
+	return client.GetTradesByMarketWithContext(context.Background(), marketId, timestamp)
+}
+
+func (client *APIClient) GetTradesByMarketWithContext(ctx context.Context, marketId string, timestamp string) (*Trade, error) {
 	var trades Trades
 	var url string
 
@@ -373,7 +874,7 @@ func (client *APIClient) GetTradesByMarket(marketId string, timestamp string) (*
 		url = fmt.Sprintf(MarketTradesEndpoint, marketId)
 	}
 
-	data, err := client.Get(url,false)
+	data, err := client.GetWithContext(ctx, url,false)
 	if err != nil {
 		return nil, err
 	}
@@ -387,9 +888,13 @@ func (client *APIClient) GetTradesByMarket(marketId string, timestamp string) (*
 }
 
 func (client *APIClient) GetBalances() ([]Balance, error) {
+	return client.GetBalancesWithContext(context.Background())
+}
+
+func (client *APIClient) GetBalancesWithContext(ctx context.Context) ([]Balance, error) {
 	var balances Balances
 
-	data, err := client.Get(BalancesEndpoint,true)
+	data, err := client.GetWithContext(ctx, BalancesEndpoint,true)
 	if err != nil {
 		return nil, err
 	}
@@ -403,9 +908,13 @@ func (client *APIClient) GetBalances() ([]Balance, error) {
 }
 
 func (client *APIClient) GetBalanceByCurrency(currency string) (*Balance, error) {
+	return client.GetBalanceByCurrencyWithContext(context.Background(), currency)
+}
+
+func (client *APIClient) GetBalanceByCurrencyWithContext(ctx context.Context, currency string) (*Balance, error) {
 	var balance BalanceSingle
 
-	data, err := client.Get(BalancesEndpoint + "/" + currency, true)
+	data, err := client.GetWithContext(ctx, BalancesEndpoint + "/" + currency, true)
 	if err != nil {
 		return nil, err
 	}
@@ -419,9 +928,13 @@ func (client *APIClient) GetBalanceByCurrency(currency string) (*Balance, error)
 }
 
 func (client *APIClient) GetOrderById(id int) (*Order, error) {
+	return client.GetOrderByIdWithContext(context.Background(), id)
+}
+
+func (client *APIClient) GetOrderByIdWithContext(ctx context.Context, id int) (*Order, error) {
 	var order OrderSingle
 
-	data, err := client.Get(fmt.Sprintf(OrderEndpoint, id), true)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(OrderEndpoint, id), true)
 	if err != nil {
 		return nil, err
 	}
@@ -434,354 +947,297 @@ func (client *APIClient) GetOrderById(id int) (*Order, error) {
 	return &order.Order, nil
 }
 
-func (client *APIClient) GetOrdersByMarket(marketId string) ([]Order, error) {
-	var orders Orders
-	var ret []Order
+func (client *APIClient) CreateOrder(marketId string, order *OrderRequest) (*Order, error) {
+	return client.CreateOrderWithContext(context.Background(), marketId, order)
+}
 
-	data, err := client.Get(fmt.Sprintf(OrdersEndpoint + "?page=1&per=" + ElementsPerPage, marketId), true)
+func (client *APIClient) CreateOrderWithContext(ctx context.Context, marketId string, order *OrderRequest) (*Order, error) {
+	var created OrderSingle
+
+	if !order.Type.Valid() {
+		return nil, fmt.Errorf("invalid order type %q", order.Type)
+	}
+
+	if !order.PriceType.Valid() {
+		return nil, fmt.Errorf("invalid price type %q", order.PriceType)
+	}
+
+	if order.PriceType == PriceTypeLimit && !order.Limit.IsPositive() {
+		return nil, fmt.Errorf("limit orders require a positive limit price")
+	}
+
+	data, err := client.PostWithContext(ctx, fmt.Sprintf(OrdersEndpoint, marketId), order, true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &orders)
+	err = json.Unmarshal(data, &created)
 	if err != nil {
 		return nil, err
 	}
 
-	resc, errc := make(chan []Order), make(chan error)
+	return &created.Order, nil
+}
 
-	ret = append(ret, orders.Orders...)
+func (client *APIClient) CancelOrder(id int) (*Order, error) {
+	return client.CancelOrderWithContext(context.Background(), id)
+}
 
-	if orders.Meta.TotalPages > 1 {
-		for i := orders.Meta.CurrentPage + 1; i <= orders.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(OrdersEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage, marketId), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &orders)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- orders.Orders
-			}(i)
-		}
+func (client *APIClient) CancelOrderWithContext(ctx context.Context, id int) (*Order, error) {
+	var order OrderSingle
 
-		for i := orders.Meta.CurrentPage + 1; i <= orders.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
+	data, err := client.PutWithContext(Idempotent(ctx), fmt.Sprintf(OrderEndpoint, id), &OrderStateRequest{State: OrderStateCanceling}, true)
+	if err != nil {
+		return nil, err
 	}
 
-	return ret, nil
+	err = json.Unmarshal(data, &order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.Order, nil
 }
 
-func (client *APIClient) GetOrdersByMarketAndState(marketId string, state string) ([]Order, error) {
-	var orders Orders
-	var ret []Order
+func (client *APIClient) CancelAllOrders(marketId string) ([]CancelResult, error) {
+	return client.CancelAllOrdersWithContext(context.Background(), marketId)
+}
 
-	data, err := client.Get(fmt.Sprintf(OrdersEndpoint + "?page=1&per=" + ElementsPerPage + "&state=" + state, marketId), true)
+func (client *APIClient) CancelAllOrdersWithContext(ctx context.Context, marketId string) ([]CancelResult, error) {
+	orders, err := client.GetOrdersByMarketAndStateWithContext(ctx, marketId, OrderStatePending)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &orders)
+	results := make([]CancelResult, len(orders))
+	sem := make(chan struct{}, CancelConcurrency)
+	var wg sync.WaitGroup
+
+	for i, order := range orders {
+		wg.Add(1)
+		go func(i int, id int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = CancelResult{ID: id, Err: ctx.Err()}
+				return
+			}
+			canceled, err := client.CancelOrderWithContext(ctx, id)
+			results[i] = CancelResult{ID: id, Order: canceled, Err: err}
+		}(i, order.ID)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+func (client *APIClient) getPage(ctx context.Context, resource string, page interface{}) error {
+	data, err := client.GetWithContext(ctx, resource, true)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	resc, errc := make(chan []Order), make(chan error)
+	return json.Unmarshal(data, page)
+}
 
-	ret = append(ret, orders.Orders...)
+func (client *APIClient) GetOrdersByMarket(marketId string) ([]Order, error) {
+	return client.GetOrdersByMarketWithContext(context.Background(), marketId)
+}
 
-	if orders.Meta.TotalPages > 1 {
-		for i := orders.Meta.CurrentPage + 1; i <= orders.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(OrdersEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage + "&state=" + state, marketId), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &orders)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- orders.Orders
-			}(i)
-		}
+func (client *APIClient) GetOrdersByMarketWithContext(ctx context.Context, marketId string) ([]Order, error) {
+	return client.OrdersPaginator(marketId, "").All(ctx, PageConcurrency)
+}
 
-		for i := orders.Meta.CurrentPage + 1; i <= orders.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
+func (client *APIClient) GetOrdersByMarketAndState(marketId string, state OrderState) ([]Order, error) {
+	return client.GetOrdersByMarketAndStateWithContext(context.Background(), marketId, state)
+}
+
+func (client *APIClient) GetOrdersByMarketAndStateWithContext(ctx context.Context, marketId string, state OrderState) ([]Order, error) {
+	if !state.Valid() {
+		return nil, fmt.Errorf("invalid order state %q", state)
 	}
 
-	return ret, nil
+	return client.OrdersPaginator(marketId, state).All(ctx, PageConcurrency)
 }
 
 
 func (client *APIClient) GetWithdrawalsByCurrency(currency string) ([]Withdrawal, error) {
-	var withdrawals Withdrawals
-	var ret []Withdrawal
+	return client.GetWithdrawalsByCurrencyWithContext(context.Background(), currency)
+}
+
+func (client *APIClient) GetWithdrawalsByCurrencyWithContext(ctx context.Context, currency string) ([]Withdrawal, error) {
+	return client.WithdrawalsPaginator(currency, "").All(ctx, PageConcurrency)
+}
 
-	data, err := client.Get(fmt.Sprintf(WithdrawalsEndpoint + "?page=1&per=" + ElementsPerPage, currency), true)
+func (client *APIClient) withdraw(ctx context.Context, currency string, withdrawal *WithdrawalRequest) (*Withdrawal, error) {
+	var created WithdrawalSingle
+
+	if !withdrawal.Amount.IsPositive() {
+		return nil, fmt.Errorf("withdrawal amount must be positive")
+	}
+
+	if withdrawal.WithdrawalData.TargetAddress == "" {
+		return nil, fmt.Errorf("withdrawal requires a target address")
+	}
+
+	data, err := client.PostWithContext(ctx, fmt.Sprintf(WithdrawalsEndpoint, currency), withdrawal, true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &withdrawals)
+	err = json.Unmarshal(data, &created)
 	if err != nil {
 		return nil, err
 	}
 
-	resc, errc := make(chan []Withdrawal), make(chan error)
-	ret = append(ret, withdrawals.Withdrawals...)
+	return &created.Withdrawal, nil
+}
 
-	if withdrawals.Meta.TotalPages > 1 {
+func (client *APIClient) CreateWithdrawal(currency string, amount decimal.Decimal, targetAddress string) (*Withdrawal, error) {
+	return client.CreateWithdrawalWithContext(context.Background(), currency, amount, targetAddress)
+}
 
-		for i := withdrawals.Meta.CurrentPage + 1; i <= withdrawals.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(WithdrawalsEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage, currency), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &withdrawals)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- withdrawals.Withdrawals
-			}(i)
-		}
+func (client *APIClient) CreateWithdrawalWithContext(ctx context.Context, currency string, amount decimal.Decimal, targetAddress string) (*Withdrawal, error) {
+	return client.withdraw(ctx, currency, &WithdrawalRequest{
+		Amount:         amount,
+		WithdrawalData: WithdrawalTarget{TargetAddress: targetAddress},
+	})
+}
 
-		for i := withdrawals.Meta.CurrentPage + 1; i <= withdrawals.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
-	}
-	return ret, nil
+func (client *APIClient) SimulateWithdrawal(currency string, amount decimal.Decimal, targetAddress string) (*Withdrawal, error) {
+	return client.SimulateWithdrawalWithContext(context.Background(), currency, amount, targetAddress)
+}
+
+func (client *APIClient) SimulateWithdrawalWithContext(ctx context.Context, currency string, amount decimal.Decimal, targetAddress string) (*Withdrawal, error) {
+	return client.withdraw(ctx, currency, &WithdrawalRequest{
+		Amount:         amount,
+		WithdrawalData: WithdrawalTarget{TargetAddress: targetAddress},
+		Simulate:       true,
+	})
 }
 
 func (client *APIClient) GetDepositsByCurrency(currency string) ([]Deposit, error) {
+	return client.GetDepositsByCurrencyWithContext(context.Background(), currency)
+}
 
-	var deposits Deposits
-	var ret []Deposit
+func (client *APIClient) GetDepositsByCurrencyWithContext(ctx context.Context, currency string) ([]Deposit, error) {
+	return client.DepositsPaginator(currency, "").All(ctx, PageConcurrency)
+}
 
-	data, err := client.Get(fmt.Sprintf(DepositsEndpoint + "?page=1&per=" + ElementsPerPage, currency), true)
-	if err != nil {
-		return nil, err
-	}
+func (client *APIClient) GetDepositsByCurrencyAndState(currency string, state DepositState) ([]Deposit, error) {
+	return client.GetDepositsByCurrencyAndStateWithContext(context.Background(), currency, state)
+}
 
-	err = json.Unmarshal(data, &deposits)
-	if err != nil {
-		return nil, err
+func (client *APIClient) GetDepositsByCurrencyAndStateWithContext(ctx context.Context, currency string, state DepositState) ([]Deposit, error) {
+	if !state.Valid() {
+		return nil, fmt.Errorf("invalid deposit state %q", state)
 	}
 
-	resc, errc := make(chan []Deposit), make(chan error)
-	ret = append(ret, deposits.Deposits...)
-
-	if deposits.Meta.TotalPages > 1 {
+	return client.DepositsPaginator(currency, state).All(ctx, PageConcurrency)
+}
 
-		for i := deposits.Meta.CurrentPage + 1; i <= deposits.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(DepositsEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage, currency), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &deposits)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- deposits.Deposits
-			}(i)
-		}
+func (client *APIClient) GetWithdrawalsByCurrencyAndState(currency string, state WithdrawalState) ([]Withdrawal, error) {
+	return client.GetWithdrawalsByCurrencyAndStateWithContext(context.Background(), currency, state)
+}
 
-		for i := deposits.Meta.CurrentPage + 1; i <= deposits.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
+func (client *APIClient) GetWithdrawalsByCurrencyAndStateWithContext(ctx context.Context, currency string, state WithdrawalState) ([]Withdrawal, error) {
+	if !state.Valid() {
+		return nil, fmt.Errorf("invalid withdrawal state %q", state)
 	}
-	return ret, nil
+
+	return client.WithdrawalsPaginator(currency, state).All(ctx, PageConcurrency)
 }
 
-func (client *APIClient) GetDepositsByCurrencyAndState(currency string, state string) ([]Deposit, error) {
+func (client *APIClient) GetWithdrawalFeeByCurrency(currency string) (*Fee, error) {
+	return client.GetWithdrawalFeeByCurrencyWithContext(context.Background(), currency)
+}
 
-	var deposits Deposits
-	var ret []Deposit
+func (client *APIClient) GetWithdrawalFeeByCurrencyWithContext(ctx context.Context, currency string) (*Fee, error) {
+	var fee FeeSingle
 
-	data, err := client.Get(fmt.Sprintf(DepositsEndpoint + "?page=1&per=" + ElementsPerPage + "&state=" + state, currency), true)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(WithdrawalFeeEndpoint, currency), true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &deposits)
+	err = json.Unmarshal(data, &fee)
 	if err != nil {
 		return nil, err
 	}
 
-	resc, errc := make(chan []Deposit), make(chan error)
-	ret = append(ret, deposits.Deposits...)
-
-	if deposits.Meta.TotalPages > 1 {
-
-		for i := deposits.Meta.CurrentPage + 1; i <= deposits.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(DepositsEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage + "&state=" + state, currency), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &deposits)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- deposits.Deposits
-			}(i)
-		}
-
-		for i := deposits.Meta.CurrentPage + 1; i <= deposits.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
-	}
-	return ret, nil
+	return &fee.Fee, nil
 }
 
-func (client *APIClient) GetWithdrawalsByCurrencyAndState(currency string, state string) ([]Withdrawal, error) {
+func (client *APIClient) GetDepositFeeByCurrency(currency string) (*Fee, error) {
+	return client.GetDepositFeeByCurrencyWithContext(context.Background(), currency)
+}
 
-	var withdrawals Withdrawals
-	var ret []Withdrawal
+func (client *APIClient) GetDepositFeeByCurrencyWithContext(ctx context.Context, currency string) (*Fee, error) {
+	var fee FeeSingle
 
-	data, err := client.Get(fmt.Sprintf(WithdrawalsEndpoint + "?page=1&per=" + ElementsPerPage + "&state=" + state, currency), true)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(DepositFeeEndpoint, currency), true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &withdrawals)
+	err = json.Unmarshal(data, &fee)
 	if err != nil {
 		return nil, err
 	}
 
-	resc, errc := make(chan []Withdrawal), make(chan error)
-	ret = append(ret, withdrawals.Withdrawals...)
-
-	if withdrawals.Meta.TotalPages > 1 {
-
-		for i := withdrawals.Meta.CurrentPage + 1; i <= withdrawals.Meta.TotalPages; i++ {
-			go func(i int) {
-				data, err := client.Get(fmt.Sprintf(WithdrawalsEndpoint + fmt.Sprintf("?page=%d", i) + "&per=" + ElementsPerPage + "&state=" + state, currency), true)
-				if err != nil {
-					errc <- err
-					return
-				}
-				err = json.Unmarshal(data, &withdrawals)
-				if err != nil {
-					errc <- err
-					return
-				}
-				resc <- withdrawals.Withdrawals
-			}(i)
-		}
+	return &fee.Fee, nil
+}
 
-		for i := withdrawals.Meta.CurrentPage + 1; i <= withdrawals.Meta.TotalPages; i++ {
-			select {
-			case res := <-resc:
-				{
-					ret = append(ret, res...)
-				}
-			case err := <-errc:
-				{
-					return nil, err
-				}
-			}
-		}
-	}
-	return ret, nil
+func (client *APIClient) ListReceiveAddresses(currency string) ([]ReceiveAddress, error) {
+	return client.ListReceiveAddressesWithContext(context.Background(), currency)
 }
 
-func (client *APIClient) GetWithdrawalFeeByCurrency(currency string) (*Fee, error) {
-	var fee FeeSingle
+func (client *APIClient) ListReceiveAddressesWithContext(ctx context.Context, currency string) ([]ReceiveAddress, error) {
+	var receiveAddresses ReceiveAddresses
 
-	data, err := client.Get(fmt.Sprintf(WithdrawalFeeEndpoint, currency), true)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(ReceiveAddressesEndpoint, currency), true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &fee)
+	err = json.Unmarshal(data, &receiveAddresses)
 	if err != nil {
 		return nil, err
 	}
 
-	return &fee.Fee, nil
+	return receiveAddresses.ReceiveAddresses, nil
 }
 
-func (client *APIClient) GetDepositFeeByCurrency(currency string) (*Fee, error) {
-	var fee FeeSingle
+func (client *APIClient) CreateReceiveAddress(currency string) (*ReceiveAddress, error) {
+	return client.CreateReceiveAddressWithContext(context.Background(), currency)
+}
+
+func (client *APIClient) CreateReceiveAddressWithContext(ctx context.Context, currency string) (*ReceiveAddress, error) {
+	var receiveAddress ReceiveAddressSingle
 
-	data, err := client.Get(fmt.Sprintf(DepositFeeEndpoint, currency), true)
+	data, err := client.PostWithContext(ctx, fmt.Sprintf(ReceiveAddressesEndpoint, currency), nil, true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &fee)
+	err = json.Unmarshal(data, &receiveAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return &fee.Fee, nil
+	return &receiveAddress.ReceiveAddress, nil
 }
 
-func (client *APIClient) GetReceiveAddresses(id int, currency string) (*ReceiveAddress, error) {
+func (client *APIClient) GetReceiveAddress(currency string, id int) (*ReceiveAddress, error) {
+	return client.GetReceiveAddressWithContext(context.Background(), currency, id)
+}
+
+func (client *APIClient) GetReceiveAddressWithContext(ctx context.Context, currency string, id int) (*ReceiveAddress, error) {
 	var receiveAddress ReceiveAddressSingle
 
-	data, err := client.Get(fmt.Sprintf(ReceiveAddressEndpoint, currency, string(id)), true)
+	data, err := client.GetWithContext(ctx, fmt.Sprintf(ReceiveAddressEndpoint, currency, id), true)
 	if err != nil {
 		return nil, err
 	}
@@ -792,5 +1248,9 @@ func (client *APIClient) GetReceiveAddresses(id int, currency string) (*ReceiveA
 	}
 
 	return &receiveAddress.ReceiveAddress, nil
+}
 
-}
\ No newline at end of file
+// Deprecated: use GetReceiveAddress, which takes the currency first.
+func (client *APIClient) GetReceiveAddresses(id int, currency string) (*ReceiveAddress, error) {
+	return client.GetReceiveAddress(currency, id)
+}
@@ -4,7 +4,13 @@ import (
 	"testing"
 	"io/ioutil"
 	"fmt"
+	"net/http"
+	"encoding/json"
+	"encoding/base64"
+	"time"
+	"context"
 	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +57,40 @@ func TestAPIClient_GetTickerByMarket(t *testing.T) {
 	assert.NotEmpty(t, markets)
 }
 
+func TestAPIClient_GetAllTickers(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	mockResponseFromFile(client.FormatResource(TickersEndpoint), "fixtures/tickers.json")
+	defer httpmock.DeactivateAndReset()
+	tickers, err := client.GetAllTickers()
+	assert.NoError(t, err)
+	assert.Len(t, tickers, 2)
+	assert.Equal(t, "ETH-BTC", tickers["ETH-BTC"].MarketID)
+	assert.Equal(t, "0.0548 BTC", tickers["ETH-BTC"].LastPrice.String())
+}
+
+func TestAPIClient_GetAllTickersFanOut(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(TickersEndpoint),
+		httpmock.NewStringResponder(404, `{"message": "Not found", "code": "not_found"}`))
+	mockResponseFromFile(client.FormatResource(MarketsEndpoint), "fixtures/markets.json")
+	mockResponseFromFile(client.FormatResource(fmt.Sprintf(MarketTickerEndpoint, "BTC-CLP")), "fixtures/market_ticker.json")
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(MarketTickerEndpoint, "BTC-COP")),
+		httpmock.NewStringResponder(200, `{"ticker": {"last_price": ["31000000.0", "COP"]}}`))
+
+	tickers, err := client.GetAllTickers()
+	assert.NoError(t, err)
+	assert.Len(t, tickers, 2)
+	assert.Equal(t, "BTC-CLP", tickers["BTC-CLP"].MarketID)
+	assert.Equal(t, "BTC-COP", tickers["BTC-COP"].MarketID)
+
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(MarketTickerEndpoint, "BTC-COP")),
+		httpmock.NewStringResponder(500, `{"message": "Internal error"}`))
+	_, err = client.GetAllTickers()
+	assert.Error(t, err)
+}
+
 func TestAPIClient_GetOrderBookByMarket(t *testing.T) {
 	client, _ := NewAPIClient("", "")
 	mockResponseFromFile(client.FormatResource(fmt.Sprintf(MarketOrderBookEndpoint, "BTC-CLP")), "fixtures/market_order_book.json")
@@ -85,6 +125,7 @@ func TestAPIClient_GetBalance(t *testing.T) {
 	markets, err := client.GetBalanceByCurrency("BTC")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, markets)
+	assert.Equal(t, "10.5274815 BTC", markets.AvailableAmount.String())
 }
 
 func TestAPIClient_GetOrdersByMarket(t *testing.T) {
@@ -121,4 +162,270 @@ func TestAPIClient_GetWithdrawalsByCurrency(t *testing.T) {
 	markets, err := client.GetWithdrawalsByCurrency("BTC-CLP")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, markets)
-}
\ No newline at end of file
+}
+func TestAPIClient_CreateOrder(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/order.json")
+	httpmock.RegisterResponder("POST", client.FormatResource(fmt.Sprintf(OrdersEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"type":"Bid","price_type":"limit","limit":1728000,"amount":0.001}`, string(body))
+			assert.NotEmpty(t, req.Header.Get("X-SBTC-SIGNATURE"))
+			return httpmock.NewStringResponse(201, string(response)), nil
+		})
+	order, err := client.CreateOrder("BTC-CLP", &OrderRequest{
+		Type:      OrderTypeBid,
+		PriceType: PriceTypeLimit,
+		Limit:     decimal.NewFromInt(1728000),
+		Amount:    decimal.RequireFromString("0.001"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, order.ID)
+}
+
+func TestAPIClient_CreateOrderWithoutLimit(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	_, err := client.CreateOrder("BTC-CLP", &OrderRequest{Type: OrderTypeAsk, PriceType: PriceTypeLimit, Amount: decimal.RequireFromString("0.001")})
+	assert.Error(t, err)
+}
+
+func TestAPIClient_CancelOrder(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/order_canceling.json")
+	httpmock.RegisterResponder("PUT", client.FormatResource(fmt.Sprintf(OrderEndpoint, 1)),
+		func(req *http.Request) (*http.Response, error) {
+			var state OrderStateRequest
+			err := json.NewDecoder(req.Body).Decode(&state)
+			assert.NoError(t, err)
+			assert.Equal(t, OrderStateCanceling, state.State)
+			assert.NotEmpty(t, req.Header.Get("X-SBTC-SIGNATURE"))
+			return httpmock.NewStringResponse(200, string(response)), nil
+		})
+	order, err := client.CancelOrder(1)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderStateCanceling, order.State)
+}
+
+func TestAPIClient_CancelAllOrders(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	mockResponseFromFile(client.FormatResource(fmt.Sprintf(OrdersEndpoint, "BTC-CLP")), "fixtures/orders_pending.json")
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/order_canceling.json")
+	httpmock.RegisterResponder("PUT", client.FormatResource(fmt.Sprintf(OrderEndpoint, 1)),
+		httpmock.NewStringResponder(200, string(response)))
+	httpmock.RegisterResponder("PUT", client.FormatResource(fmt.Sprintf(OrderEndpoint, 2)),
+		httpmock.NewErrorResponder(fmt.Errorf("connection reset")))
+	results, err := client.CancelAllOrders("BTC-CLP")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 1, results[0].ID)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 2, results[1].ID)
+	assert.Error(t, results[1].Err)
+}
+
+func TestAPIClient_SignedMethodsKeepBody(t *testing.T) {
+	client, _ := NewAPIClient("key", "secret")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	methods := map[string]func(string, interface{}, bool) ([]byte, error){
+		"POST":   client.Post,
+		"PUT":    client.Put,
+		"DELETE": client.Delete,
+	}
+
+	for method, call := range methods {
+		httpmock.RegisterResponder(method, client.FormatResource("/resource"),
+			func(req *http.Request) (*http.Response, error) {
+				body, err := ioutil.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"state":"canceling"}`, string(body))
+				assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+				assert.Equal(t, "key", req.Header.Get("X-SBTC-APIKEY"))
+				expected := client.SignRequest(req.Method, req.URL.RequestURI(),
+					base64.StdEncoding.EncodeToString(body), req.Header.Get("X-SBTC-NONCE"))
+				assert.Equal(t, expected, req.Header.Get("X-SBTC-SIGNATURE"))
+				return httpmock.NewStringResponse(200, "{}"), nil
+			})
+		_, err := call("/resource", &OrderStateRequest{State: "canceling"}, true)
+		assert.NoError(t, err, method)
+	}
+}
+
+func TestAPIClient_SignedRequestWithoutBody(t *testing.T) {
+	client, _ := NewAPIClient("key", "secret")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("DELETE", client.FormatResource("/resource"),
+		func(req *http.Request) (*http.Response, error) {
+			expected := client.SignRequest(req.Method, req.URL.RequestURI(), req.Header.Get("X-SBTC-NONCE"))
+			assert.Equal(t, expected, req.Header.Get("X-SBTC-SIGNATURE"))
+			assert.Empty(t, req.Header.Get("Content-Type"))
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+	_, err := client.Delete("/resource", nil, true)
+	assert.NoError(t, err)
+}
+
+func TestAPIClient_CreateWithdrawal(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/withdrawal.json")
+	httpmock.RegisterResponder("POST", client.FormatResource(fmt.Sprintf(WithdrawalsEndpoint, "BTC")),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"amount":0.35,"withdrawal_data":{"target_address":"mo366JJaDU5B1hmnPygyjQVMbUKnBC7DsY"}}`, string(body))
+			return httpmock.NewStringResponse(201, string(response)), nil
+		})
+	withdrawal, err := client.CreateWithdrawal("BTC", decimal.RequireFromString("0.35"), "mo366JJaDU5B1hmnPygyjQVMbUKnBC7DsY")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, withdrawal.ID)
+}
+
+func TestAPIClient_SimulateWithdrawal(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/withdrawal_simulated.json")
+	httpmock.RegisterResponder("POST", client.FormatResource(fmt.Sprintf(WithdrawalsEndpoint, "BTC")),
+		func(req *http.Request) (*http.Response, error) {
+			var withdrawal WithdrawalRequest
+			err := json.NewDecoder(req.Body).Decode(&withdrawal)
+			assert.NoError(t, err)
+			assert.True(t, withdrawal.Simulate)
+			return httpmock.NewStringResponse(201, string(response)), nil
+		})
+	withdrawal, err := client.SimulateWithdrawal("BTC", decimal.RequireFromString("0.35"), "mo366JJaDU5B1hmnPygyjQVMbUKnBC7DsY")
+	assert.NoError(t, err)
+	assert.Equal(t, WithdrawalStateSimulated, withdrawal.State)
+	assert.Equal(t, "0.00001 BTC", withdrawal.Fee.String())
+}
+
+func TestAPIClient_ListReceiveAddresses(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	mockResponseFromFile(client.FormatResource(fmt.Sprintf(ReceiveAddressesEndpoint, "BTC")), "fixtures/receive_addresses.json")
+	defer httpmock.DeactivateAndReset()
+	addresses, err := client.ListReceiveAddresses("BTC")
+	assert.NoError(t, err)
+	assert.Len(t, addresses, 2)
+	assert.Equal(t, 2017, addresses[1].UpdatedAt.Year())
+}
+
+func TestAPIClient_CreateReceiveAddress(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/receive_address.json")
+	httpmock.RegisterResponder("POST", client.FormatResource(fmt.Sprintf(ReceiveAddressesEndpoint, "BTC")),
+		httpmock.NewStringResponder(201, string(response)))
+	address, err := client.CreateReceiveAddress("BTC")
+	assert.NoError(t, err)
+	assert.Equal(t, "mo366JJaDU5B1hmnPygyjQVMbUKnBC7DsY", address.Address)
+}
+
+func TestAPIClient_GetReceiveAddress(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	mockResponseFromFile(client.FormatResource("/currencies/BTC/receive_addresses/12"), "fixtures/receive_address.json")
+	defer httpmock.DeactivateAndReset()
+	address, err := client.GetReceiveAddress("BTC", 12)
+	assert.NoError(t, err)
+	assert.False(t, address.CreatedAt.IsZero())
+}
+
+func TestAPIClient_GetQuotation(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/market_quotation.json")
+	httpmock.RegisterResponder("POST", client.FormatResource(fmt.Sprintf(MarketQuotationsEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"type":"bid_given_size","amount":1}`, string(body))
+			assert.Empty(t, req.Header.Get("X-SBTC-SIGNATURE"))
+			return httpmock.NewStringResponse(200, string(response)), nil
+		})
+	quotation, err := client.GetQuotation("BTC-CLP", &QuotationRequest{Type: QuotationBidGivenSize, Amount: decimal.NewFromInt(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.004 BTC", quotation.Fee.String())
+	assert.Equal(t, "836985.26 CLP", quotation.QuoteExchanged.String())
+}
+
+func TestAPIClient_GetQuotationUnknownType(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	_, err := client.GetQuotation("BTC-CLP", &QuotationRequest{Type: "bid_given_price", Amount: decimal.NewFromInt(1)})
+	assert.Error(t, err)
+}
+
+func TestAPIClient_GetAveragePrices(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/market_average_prices.json")
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(MarketReportsEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "average_prices", req.URL.Query().Get("report_type"))
+			assert.Equal(t, "1476835200", req.URL.Query().Get("from"))
+			assert.Equal(t, "1476842400", req.URL.Query().Get("to"))
+			return httpmock.NewStringResponse(200, string(response)), nil
+		})
+	prices, err := client.GetAveragePrices("BTC-CLP", time.Unix(1476835200, 0), time.Unix(1476842400, 0))
+	assert.NoError(t, err)
+	assert.Len(t, prices, 3)
+	assert.Equal(t, time.Unix(1476838800, 0).UTC(), prices[1].Timestamp)
+	assert.Equal(t, "433845.12", prices[1].Average.String())
+}
+
+func TestAPIClient_GetCandles(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	mockResponseFromFile(client.FormatResource(fmt.Sprintf(MarketReportsEndpoint, "BTC-CLP")), "fixtures/market_candles.json")
+	defer httpmock.DeactivateAndReset()
+	candles, err := client.GetCandles("BTC-CLP", time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, candles, 2)
+	candle := candles[1]
+	assert.Equal(t, time.Unix(1476838800, 0).UTC(), candle.Timestamp)
+	assert.Equal(t, "432010.5", candle.Open.String())
+	assert.Equal(t, "435283.3", candle.Close.String())
+	assert.Equal(t, "435447.12", candle.High.String())
+	assert.Equal(t, "431950", candle.Low.String())
+	assert.Equal(t, "4.10035818", candle.Volume.String())
+}
+
+func TestAPIClient_GetMarketsWithCanceledContext(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(MarketsEndpoint),
+		func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetMarketsWithContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAPIClient_GetOrdersByMarketWithContextTimeout(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/orders.json")
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(OrdersEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("page") != "1" {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return httpmock.NewStringResponse(200, string(response)), nil
+		})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetOrdersByMarketWithContext(ctx, "BTC-CLP")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
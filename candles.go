@@ -0,0 +1,199 @@
+package buda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrLateTrade = errors.New("trade belongs to a candle that was already flushed")
+
+// TradeCandle is an OHLCV bucket built from individual trades. Amounts are in
+// the base currency; buy and sell volume follow the taker direction.
+type TradeCandle struct {
+	Start      time.Time
+	Interval   time.Duration
+	Open       decimal.Decimal
+	High       decimal.Decimal
+	Low        decimal.Decimal
+	Close      decimal.Decimal
+	Volume     decimal.Decimal
+	BuyVolume  decimal.Decimal
+	SellVolume decimal.Decimal
+	Trades     int
+}
+
+type candleBucket struct {
+	candle TradeCandle
+	first  TradeEntry
+	last   TradeEntry
+	seen   map[string]bool
+}
+
+// CandleBuilder aggregates trades into candles of a fixed interval, aligned
+// to the Unix epoch. Trades may arrive in any order and more than once: open
+// and close are picked by timestamp, then trade id, and duplicates are
+// ignored, so the result only depends on the set of trades added. Once a
+// candle is flushed its trades are rejected with ErrLateTrade.
+type CandleBuilder struct {
+	Interval time.Duration
+
+	buckets map[int64]*candleBucket
+	flushed time.Time
+}
+
+func NewCandleBuilder(interval time.Duration) *CandleBuilder {
+	return &CandleBuilder{
+		Interval: interval,
+		buckets:  make(map[int64]*candleBucket),
+	}
+}
+
+// bucketStart returns the start of the interval t falls in, counting
+// intervals from the Unix epoch.
+func bucketStart(t time.Time, interval time.Duration) time.Time {
+	nanos := t.UnixNano()
+	offset := nanos % int64(interval)
+	if offset < 0 {
+		offset += int64(interval)
+	}
+	return time.Unix(0, nanos-offset).UTC()
+}
+
+// tradeBefore orders trades deterministically, even when they share a
+// timestamp.
+func tradeBefore(a TradeEntry, b TradeEntry) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	if a.ID != b.ID {
+		return a.ID < b.ID
+	}
+	return tradeKey(a) < tradeKey(b)
+}
+
+// Add folds a trade into its candle and returns the candle as it stands.
+func (builder *CandleBuilder) Add(entry TradeEntry) (TradeCandle, error) {
+	if builder.Interval <= 0 {
+		return TradeCandle{}, fmt.Errorf("candle interval must be positive, got %s", builder.Interval)
+	}
+
+	start := bucketStart(entry.Timestamp, builder.Interval)
+	if !builder.flushed.IsZero() && start.Before(builder.flushed) {
+		return TradeCandle{}, ErrLateTrade
+	}
+
+	bucket, ok := builder.buckets[start.UnixNano()]
+	if !ok {
+		bucket = &candleBucket{
+			candle: TradeCandle{
+				Start:    start,
+				Interval: builder.Interval,
+				High:     entry.Price,
+				Low:      entry.Price,
+			},
+			first: entry,
+			last:  entry,
+			seen:  make(map[string]bool),
+		}
+		builder.buckets[start.UnixNano()] = bucket
+	}
+
+	key := tradeKey(entry)
+	if bucket.seen[key] {
+		return bucket.candle, nil
+	}
+	bucket.seen[key] = true
+
+	if tradeBefore(entry, bucket.first) {
+		bucket.first = entry
+	}
+	if tradeBefore(bucket.last, entry) {
+		bucket.last = entry
+	}
+
+	candle := &bucket.candle
+	candle.Open = bucket.first.Price
+	candle.Close = bucket.last.Price
+	if entry.Price.GreaterThan(candle.High) {
+		candle.High = entry.Price
+	}
+	if entry.Price.LessThan(candle.Low) {
+		candle.Low = entry.Price
+	}
+
+	candle.Volume = candle.Volume.Add(entry.Amount)
+	switch entry.Direction {
+	case TradeDirectionBuy:
+		candle.BuyVolume = candle.BuyVolume.Add(entry.Amount)
+	case TradeDirectionSell:
+		candle.SellVolume = candle.SellVolume.Add(entry.Amount)
+	}
+	candle.Trades++
+
+	return *candle, nil
+}
+
+// AddAll adds every entry, skipping the ones rejected as late, and returns
+// how many were rejected.
+func (builder *CandleBuilder) AddAll(entries []TradeEntry) (int, error) {
+	late := 0
+
+	for _, entry := range entries {
+		_, err := builder.Add(entry)
+		if err == ErrLateTrade {
+			late++
+			continue
+		}
+		if err != nil {
+			return late, err
+		}
+	}
+
+	return late, nil
+}
+
+// Candles returns the candles that have not been flushed, oldest first.
+func (builder *CandleBuilder) Candles() []TradeCandle {
+	return builder.collect(func(*candleBucket) bool { return true }, false)
+}
+
+// Flush removes and returns, oldest first, every candle that ends at or
+// before the given time. Trades for those candles are rejected from then on.
+func (builder *CandleBuilder) Flush(before time.Time) []TradeCandle {
+	if builder.Interval <= 0 {
+		return nil
+	}
+
+	cutoff := bucketStart(before, builder.Interval)
+	if cutoff.After(builder.flushed) {
+		builder.flushed = cutoff
+	}
+
+	return builder.collect(func(bucket *candleBucket) bool {
+		return bucket.candle.Start.Before(builder.flushed)
+	}, true)
+}
+
+func (builder *CandleBuilder) collect(include func(*candleBucket) bool, remove bool) []TradeCandle {
+	var candles []TradeCandle
+
+	for start, bucket := range builder.buckets {
+		if !include(bucket) {
+			continue
+		}
+		candles = append(candles, bucket.candle)
+		if remove {
+			delete(builder.buckets, start)
+		}
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Start.Before(candles[j].Start)
+	})
+
+	return candles
+}
@@ -0,0 +1,114 @@
+package buda
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func candleTrade(seconds int64, price string, amount string, direction TradeDirection, id int64) TradeEntry {
+	return TradeEntry{
+		Timestamp: time.Unix(seconds, 0).UTC(),
+		Amount:    decimal.RequireFromString(amount),
+		Price:     decimal.RequireFromString(price),
+		Direction: direction,
+		ID:        id,
+	}
+}
+
+func TestCandleBuilder_OHLCV(t *testing.T) {
+	trades := []TradeEntry{
+		candleTrade(60, "100", "1", TradeDirectionBuy, 1),
+		candleTrade(90, "110", "0.5", TradeDirectionSell, 2),
+		candleTrade(100, "95", "2", TradeDirectionSell, 3),
+		candleTrade(119, "105", "1", TradeDirectionBuy, 4),
+		candleTrade(125, "107", "3", TradeDirectionBuy, 5),
+	}
+
+	builder := NewCandleBuilder(time.Minute)
+	late, err := builder.AddAll(trades)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, late)
+
+	candles := builder.Candles()
+	assert.Len(t, candles, 2)
+
+	candle := candles[0]
+	assert.Equal(t, time.Unix(60, 0).UTC(), candle.Start)
+	assertDecimal(t, "100", candle.Open)
+	assertDecimal(t, "110", candle.High)
+	assertDecimal(t, "95", candle.Low)
+	assertDecimal(t, "105", candle.Close)
+	assertDecimal(t, "4.5", candle.Volume)
+	assertDecimal(t, "2", candle.BuyVolume)
+	assertDecimal(t, "2.5", candle.SellVolume)
+	assert.Equal(t, 4, candle.Trades)
+
+	assertDecimal(t, "107", candles[1].Open)
+	assertDecimal(t, "107", candles[1].Close)
+}
+
+func TestCandleBuilder_OrderIndependent(t *testing.T) {
+	trades := []TradeEntry{
+		candleTrade(60, "100", "1", TradeDirectionBuy, 1),
+		candleTrade(60, "101", "1", TradeDirectionBuy, 2),
+		candleTrade(80, "99", "1", TradeDirectionSell, 3),
+		candleTrade(119, "103", "1", TradeDirectionBuy, 4),
+		candleTrade(119, "102", "1", TradeDirectionSell, 5),
+	}
+
+	forward := NewCandleBuilder(time.Minute)
+	forward.AddAll(trades)
+
+	backward := NewCandleBuilder(time.Minute)
+	for i := len(trades) - 1; i >= 0; i-- {
+		backward.Add(trades[i])
+	}
+	backward.Add(trades[2])
+
+	assert.Equal(t, forward.Candles(), backward.Candles())
+	candle := backward.Candles()[0]
+	assertDecimal(t, "100", candle.Open)
+	assertDecimal(t, "102", candle.Close)
+	assert.Equal(t, 5, candle.Trades)
+}
+
+func TestCandleBuilder_FlushRejectsLateTrades(t *testing.T) {
+	builder := NewCandleBuilder(5 * time.Minute)
+	builder.Add(candleTrade(0, "100", "1", TradeDirectionBuy, 1))
+	builder.Add(candleTrade(310, "101", "1", TradeDirectionBuy, 2))
+
+	flushed := builder.Flush(time.Unix(400, 0))
+	assert.Len(t, flushed, 1)
+	assert.Equal(t, time.Unix(0, 0).UTC(), flushed[0].Start)
+	assert.Len(t, builder.Candles(), 1)
+
+	_, err := builder.Add(candleTrade(120, "99", "1", TradeDirectionSell, 3))
+	assert.Equal(t, ErrLateTrade, err)
+
+	candle, err := builder.Add(candleTrade(305, "98", "1", TradeDirectionSell, 4))
+	assert.NoError(t, err)
+	assertDecimal(t, "98", candle.Open)
+	assertDecimal(t, "101", candle.Close)
+
+	_, err = NewCandleBuilder(0).Add(candleTrade(0, "1", "1", TradeDirectionBuy, 1))
+	assert.Error(t, err)
+}
+
+func TestCandleBuilder_AlignsToUnixEpoch(t *testing.T) {
+	weekly := NewCandleBuilder(7 * 24 * time.Hour)
+	candle, err := weekly.Add(candleTrade(time.Date(2023, 11, 15, 12, 0, 0, 0, time.UTC).Unix(), "1", "1", TradeDirectionBuy, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 11, 9, 0, 0, 0, 0, time.UTC), candle.Start)
+
+	sevenMinutes := NewCandleBuilder(7 * time.Minute)
+	candle, _ = sevenMinutes.Add(candleTrade(1000, "1", "1", TradeDirectionBuy, 1))
+	assert.Equal(t, time.Unix(840, 0).UTC(), candle.Start)
+
+	candle, _ = sevenMinutes.Add(candleTrade(-1, "1", "1", TradeDirectionBuy, 2))
+	assert.Equal(t, time.Unix(-420, 0).UTC(), candle.Start)
+
+	assert.Empty(t, NewCandleBuilder(0).Flush(time.Unix(1000, 0)))
+}
@@ -0,0 +1,177 @@
+package buda
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type OrderType string
+
+const (
+	OrderTypeBid OrderType = "Bid"
+	OrderTypeAsk OrderType = "Ask"
+)
+
+type OrderState string
+
+const (
+	OrderStateReceived  OrderState = "received"
+	OrderStatePending   OrderState = "pending"
+	OrderStateTraded    OrderState = "traded"
+	OrderStateCanceling OrderState = "canceling"
+	OrderStateCanceled  OrderState = "canceled"
+)
+
+type PriceType string
+
+const (
+	PriceTypeLimit  PriceType = "limit"
+	PriceTypeMarket PriceType = "market"
+)
+
+type DepositState string
+
+const (
+	DepositStatePendingInfo DepositState = "pending_info"
+	DepositStatePending     DepositState = "pending"
+	DepositStateConfirmed   DepositState = "confirmed"
+	DepositStateAnulled     DepositState = "anulled"
+	DepositStateRetained    DepositState = "retained"
+)
+
+type WithdrawalState string
+
+const (
+	WithdrawalStatePendingPreparation WithdrawalState = "pending_preparation"
+	WithdrawalStatePendingSignature   WithdrawalState = "pending_signature"
+	WithdrawalStatePending            WithdrawalState = "pending"
+	WithdrawalStateConfirmed          WithdrawalState = "confirmed"
+	WithdrawalStateRejected           WithdrawalState = "rejected"
+	WithdrawalStateAnulled            WithdrawalState = "anulled"
+	WithdrawalStateSimulated          WithdrawalState = "simulated"
+)
+
+type TradeDirection string
+
+const (
+	TradeDirectionBuy  TradeDirection = "buy"
+	TradeDirectionSell TradeDirection = "sell"
+)
+
+func (orderType OrderType) Valid() bool {
+	switch orderType {
+	case OrderTypeBid, OrderTypeAsk:
+		return true
+	}
+	return false
+}
+
+func (state OrderState) Valid() bool {
+	switch state {
+	case OrderStateReceived, OrderStatePending, OrderStateTraded, OrderStateCanceling, OrderStateCanceled:
+		return true
+	}
+	return false
+}
+
+func (priceType PriceType) Valid() bool {
+	switch priceType {
+	case PriceTypeLimit, PriceTypeMarket:
+		return true
+	}
+	return false
+}
+
+func (state DepositState) Valid() bool {
+	switch state {
+	case DepositStatePendingInfo, DepositStatePending, DepositStateConfirmed, DepositStateAnulled, DepositStateRetained:
+		return true
+	}
+	return false
+}
+
+func (state WithdrawalState) Valid() bool {
+	switch state {
+	case WithdrawalStatePendingPreparation, WithdrawalStatePendingSignature, WithdrawalStatePending,
+		WithdrawalStateConfirmed, WithdrawalStateRejected, WithdrawalStateAnulled, WithdrawalStateSimulated:
+		return true
+	}
+	return false
+}
+
+func (direction TradeDirection) Valid() bool {
+	switch direction {
+	case TradeDirectionBuy, TradeDirectionSell:
+		return true
+	}
+	return false
+}
+
+func unmarshalEnum(data []byte, kind string, valid func(string) bool) (string, error) {
+	var value string
+
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return "", err
+	}
+
+	if !valid(value) {
+		return "", fmt.Errorf("invalid %s %q", kind, value)
+	}
+
+	return value, nil
+}
+
+func (orderType *OrderType) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "order type", func(v string) bool { return OrderType(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*orderType = OrderType(value)
+	return nil
+}
+
+func (state *OrderState) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "order state", func(v string) bool { return OrderState(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*state = OrderState(value)
+	return nil
+}
+
+func (priceType *PriceType) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "price type", func(v string) bool { return PriceType(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*priceType = PriceType(value)
+	return nil
+}
+
+func (state *DepositState) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "deposit state", func(v string) bool { return DepositState(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*state = DepositState(value)
+	return nil
+}
+
+func (state *WithdrawalState) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "withdrawal state", func(v string) bool { return WithdrawalState(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*state = WithdrawalState(value)
+	return nil
+}
+
+func (direction *TradeDirection) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "trade direction", func(v string) bool { return TradeDirection(v).Valid() })
+	if err != nil {
+		return err
+	}
+	*direction = TradeDirection(value)
+	return nil
+}
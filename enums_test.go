@@ -0,0 +1,42 @@
+package buda
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnums_UnmarshalJSON(t *testing.T) {
+	var order Order
+	err := json.Unmarshal([]byte(`{"type": "Ask", "state": "canceled", "price_type": "market"}`), &order)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderTypeAsk, order.Type)
+	assert.Equal(t, OrderStateCanceled, order.State)
+	assert.Equal(t, PriceTypeMarket, order.PriceType)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "bid"}`), &order))
+	assert.Error(t, json.Unmarshal([]byte(`{"state": "cancelled"}`), &order))
+	assert.Error(t, json.Unmarshal([]byte(`{"price_type": "stop"}`), &order))
+
+	var deposit Deposit
+	assert.NoError(t, json.Unmarshal([]byte(`{"state": "pending_info"}`), &deposit))
+	assert.Equal(t, DepositStatePendingInfo, deposit.State)
+	assert.Error(t, json.Unmarshal([]byte(`{"state": "done"}`), &deposit))
+
+	var withdrawal Withdrawal
+	assert.NoError(t, json.Unmarshal([]byte(`{"state": "rejected"}`), &withdrawal))
+	assert.Equal(t, WithdrawalStateRejected, withdrawal.State)
+	assert.Error(t, json.Unmarshal([]byte(`{"state": "done"}`), &withdrawal))
+}
+
+func TestAPIClient_StateFilterValidation(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+
+	_, err := client.GetOrdersByMarketAndState("BTC-CLP", OrderState("pendng"))
+	assert.Error(t, err)
+	_, err = client.GetDepositsByCurrencyAndState("BTC", DepositState("pending&page=2"))
+	assert.Error(t, err)
+	_, err = client.GetWithdrawalsByCurrencyAndState("BTC", WithdrawalState(""))
+	assert.Error(t, err)
+}
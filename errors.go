@@ -0,0 +1,64 @@
+package buda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is returned for any response with a non-2xx status code.
+type APIError struct {
+	StatusCode int           `json:"-"`
+	Method     string        `json:"-"`
+	Endpoint   string        `json:"-"`
+	RetryAfter time.Duration `json:"-"`
+	Code       string        `json:"code"`
+	Message    string        `json:"message"`
+}
+
+func newAPIError(response *http.Response, method string, endpoint string, body []byte) *APIError {
+	apiError := &APIError{}
+
+	if json.Unmarshal(body, apiError) != nil || (apiError.Code == "" && apiError.Message == "") {
+		apiError.Message = strings.TrimSpace(string(body))
+	}
+
+	apiError.StatusCode = response.StatusCode
+	apiError.Method = method
+	apiError.Endpoint = endpoint
+	apiError.RetryAfter = parseRetryAfter(response.Header.Get("Retry-After"))
+
+	return apiError
+}
+
+func (err *APIError) Error() string {
+	message := err.Message
+	if message == "" {
+		message = http.StatusText(err.StatusCode)
+	}
+
+	if err.Code != "" {
+		return fmt.Sprintf("buda: %s %s: %d %s: %s", err.Method, err.Endpoint, err.StatusCode, err.Code, message)
+	}
+	return fmt.Sprintf("buda: %s %s: %d: %s", err.Method, err.Endpoint, err.StatusCode, message)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiError *APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == status
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
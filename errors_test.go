@@ -0,0 +1,60 @@
+package buda
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_NotFound(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(OrderEndpoint, 1)),
+		httpmock.NewStringResponder(404, `{"message": "Not found", "code": "not_found"}`))
+
+	order, err := client.GetOrderById(1)
+	assert.Nil(t, order)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsUnauthorized(err))
+
+	apiError, ok := err.(*APIError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, apiError.StatusCode)
+	assert.Equal(t, "not_found", apiError.Code)
+	assert.Equal(t, "Not found", apiError.Message)
+	assert.Equal(t, "GET", apiError.Method)
+	assert.Equal(t, fmt.Sprintf(OrderEndpoint, 1), apiError.Endpoint)
+	assert.Equal(t, "buda: GET /orders/1: 404 not_found: Not found", err.Error())
+}
+
+func TestAPIError_Unauthorized(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(BalancesEndpoint),
+		httpmock.NewStringResponder(401, `{"message": "You are not authorized", "code": "authentication_error"}`))
+
+	_, err := client.GetBalances()
+	assert.True(t, IsUnauthorized(err))
+}
+
+func TestAPIError_RateLimited(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(MarketsEndpoint),
+		httpmock.NewStringResponder(429, "Retry later\n"))
+
+	_, err := client.GetMarkets()
+	assert.True(t, IsRateLimited(err))
+	assert.Equal(t, "Retry later", err.(*APIError).Message)
+}
+
+func TestAPIError_Helpers(t *testing.T) {
+	assert.False(t, IsNotFound(nil))
+	assert.False(t, IsNotFound(fmt.Errorf("not found")))
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", &APIError{StatusCode: 404})))
+}
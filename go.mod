@@ -0,0 +1,18 @@
+module github.com/niedbalski/go-buda
+
+go 1.18
+
+require (
+	github.com/google/btree v1.1.2
+	github.com/gorilla/websocket v1.5.3
+	github.com/jarcoal/httpmock v1.3.1
+	github.com/shopspring/decimal v1.4.0
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/time v0.5.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
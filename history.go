@@ -0,0 +1,153 @@
+package buda
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TradeHistoryCheckpoint captures where a TradeHistory stopped so a later
+// run can carry on without re-emitting trades. It is safe to store as JSON.
+type TradeHistoryCheckpoint struct {
+	MarketId string    `json:"market_id"`
+	Cursor   time.Time `json:"cursor"`
+	End      time.Time `json:"end"`
+	Boundary []string  `json:"boundary"`
+	Done     bool      `json:"done"`
+}
+
+// TradeHistory walks the trades of a market backwards in time, from a start
+// time down to an end time, one page per call to Next.
+type TradeHistory struct {
+	client   *APIClient
+	marketId string
+	cursor   time.Time
+	end      time.Time
+	boundary map[string]bool
+	done     bool
+}
+
+// TradeHistory starts at start (or the latest trade when start is zero) and
+// stops once trades older than end are reached.
+func (client *APIClient) TradeHistory(marketId string, start time.Time, end time.Time) *TradeHistory {
+	return &TradeHistory{
+		client:   client,
+		marketId: marketId,
+		cursor:   start,
+		end:      end,
+		boundary: make(map[string]bool),
+	}
+}
+
+func (client *APIClient) ResumeTradeHistory(checkpoint TradeHistoryCheckpoint) *TradeHistory {
+	history := client.TradeHistory(checkpoint.MarketId, checkpoint.Cursor, checkpoint.End)
+	history.done = checkpoint.Done
+
+	for _, key := range checkpoint.Boundary {
+		history.boundary[key] = true
+	}
+
+	return history
+}
+
+func tradeKey(entry TradeEntry) string {
+	if entry.ID != 0 {
+		return fmt.Sprintf("id:%d", entry.ID)
+	}
+	return fmt.Sprintf("%d|%s|%s|%s", entry.Timestamp.UnixNano(), entry.Amount, entry.Price, entry.Direction)
+}
+
+func (history *TradeHistory) HasNext() bool {
+	return !history.done
+}
+
+// Next returns the next batch of trades, newest first, skipping any trade
+// already returned by a previous page.
+func (history *TradeHistory) Next(ctx context.Context) ([]TradeEntry, error) {
+	var entries []TradeEntry
+
+	if history.done {
+		return nil, ErrNoMorePages
+	}
+
+	timestamp := ""
+	if !history.cursor.IsZero() {
+		timestamp = FormatMillis(history.cursor)
+	}
+
+	trade, err := history.client.GetTradesByMarketWithContext(ctx, history.marketId, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trade.Entries) == 0 {
+		history.done = true
+		return nil, nil
+	}
+
+	for _, entry := range trade.Entries {
+		if !history.end.IsZero() && entry.Timestamp.Before(history.end) {
+			history.done = true
+			continue
+		}
+
+		if !history.cursor.IsZero() && entry.Timestamp.After(history.cursor) {
+			continue
+		}
+
+		if history.boundary[tradeKey(entry)] {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	next := trade.LastTimestamp
+	if next.IsZero() {
+		next = trade.Entries[len(trade.Entries)-1].Timestamp
+	}
+
+	boundary := make(map[string]bool)
+	if next.Equal(history.cursor) {
+		boundary = history.boundary
+	}
+
+	for _, entry := range trade.Entries {
+		if entry.Timestamp.Equal(next) {
+			boundary[tradeKey(entry)] = true
+		}
+	}
+
+	// A full page of trades sharing one millisecond would otherwise be
+	// requested forever; step past it.
+	if next.Equal(history.cursor) && len(entries) == 0 {
+		next = next.Add(-time.Millisecond)
+		boundary = make(map[string]bool)
+	}
+
+	history.cursor = next
+	history.boundary = boundary
+
+	if !history.end.IsZero() && history.cursor.Before(history.end) {
+		history.done = true
+	}
+
+	return entries, nil
+}
+
+func (history *TradeHistory) Checkpoint() TradeHistoryCheckpoint {
+	checkpoint := TradeHistoryCheckpoint{
+		MarketId: history.marketId,
+		Cursor:   history.cursor,
+		End:      history.end,
+		Done:     history.done,
+	}
+
+	for key := range history.boundary {
+		checkpoint.Boundary = append(checkpoint.Boundary, key)
+	}
+	sort.Strings(checkpoint.Boundary)
+
+	return checkpoint
+}
@@ -0,0 +1,86 @@
+package buda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTradeTape serves trades newest first, pageSize at a time, the way the
+// trades endpoint does: every entry at or before the requested timestamp.
+func mockTradeTape(client *APIClient, timestamps []int64, pageSize int) {
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(MarketTradesEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			limit := int64(1 << 62)
+			if value := req.URL.Query().Get("timestamp"); value != "" {
+				limit, _ = strconv.ParseInt(value, 10, 64)
+			}
+
+			var entries []string
+			var last int64
+			for i, timestamp := range timestamps {
+				if timestamp > limit || len(entries) == pageSize {
+					continue
+				}
+				entries = append(entries, fmt.Sprintf(`["%d", "0.1", "1000.0", "buy", %d]`, timestamp, i+1))
+				last = timestamp
+			}
+
+			body := fmt.Sprintf(`{"trades": {"market_id": "BTC-CLP", "timestamp": "%d", "last_timestamp": "%d", "entries": [%s]}}`,
+				limit, last, strings.Join(entries, ","))
+			return httpmock.NewStringResponse(200, body), nil
+		})
+}
+
+func collectHistory(t *testing.T, history *TradeHistory, pages int) []int64 {
+	var ids []int64
+	for i := 0; i < pages && history.HasNext(); i++ {
+		entries, err := history.Next(context.Background())
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+	}
+	return ids
+}
+
+func TestTradeHistory_DeduplicatesBoundaries(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockTradeTape(client, []int64{9000, 8000, 7000, 7000, 6000, 5000, 5000, 5000, 4000, 3000}, 3)
+
+	history := client.TradeHistory("BTC-CLP", time.Time{}, time.Unix(0, 4000*int64(time.Millisecond)))
+	ids := collectHistory(t, history, 100)
+
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9}, ids)
+	assert.False(t, history.HasNext())
+}
+
+func TestTradeHistory_ResumeFromCheckpoint(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockTradeTape(client, []int64{9000, 8000, 7000, 7000, 6000, 5000, 5000, 5000, 4000, 3000}, 3)
+
+	history := client.TradeHistory("BTC-CLP", time.Unix(9, 0), time.Time{})
+	first := collectHistory(t, history, 2)
+
+	data, err := json.Marshal(history.Checkpoint())
+	assert.NoError(t, err)
+	var checkpoint TradeHistoryCheckpoint
+	assert.NoError(t, json.Unmarshal(data, &checkpoint))
+
+	resumed := client.ResumeTradeHistory(checkpoint)
+	rest := collectHistory(t, resumed, 100)
+
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, append(first, rest...))
+}
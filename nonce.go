@@ -0,0 +1,64 @@
+package buda
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NonceSource produces the X-SBTC-NONCE value for signed requests. Buda
+// rejects any nonce that is not greater than the last one seen for a key.
+type NonceSource interface {
+	Nonce() string
+}
+
+// defaultNonce is shared by every client in the process, so clients using
+// the same key never issue equal or decreasing nonces. WithNonceSource
+// replaces it for a single client.
+var defaultNonce = NewMonotonicNonce()
+
+// MonotonicNonce issues microsecond timestamps, bumping past the previous
+// value whenever the clock stalls or goes backwards, so concurrent callers
+// never share or reuse a nonce.
+type MonotonicNonce struct {
+	mutex sync.Mutex
+	last  int64
+	now   func() time.Time
+}
+
+func NewMonotonicNonce() *MonotonicNonce {
+	return &MonotonicNonce{now: time.Now}
+}
+
+func (source *MonotonicNonce) Nonce() string {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	now := time.Now
+	if source.now != nil {
+		now = source.now
+	}
+
+	next := now().UnixNano() / int64(time.Microsecond)
+	if next <= source.last {
+		next = source.last + 1
+	}
+	source.last = next
+
+	return strconv.FormatInt(next, 10)
+}
+
+// CounterNonce returns consecutive integers starting after Start, which makes
+// signatures reproducible in tests.
+type CounterNonce struct {
+	mutex sync.Mutex
+	Start int64
+}
+
+func (source *CounterNonce) Nonce() string {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.Start++
+	return strconv.FormatInt(source.Start, 10)
+}
@@ -0,0 +1,73 @@
+package buda
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonotonicNonce_Concurrent(t *testing.T) {
+	source := NewMonotonicNonce()
+	seen := make(map[string]bool)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				nonce := source.Nonce()
+				mutex.Lock()
+				seen[nonce] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 4000)
+}
+
+func TestMonotonicNonce_ClockGoesBackwards(t *testing.T) {
+	clock := time.Unix(1500000000, 0)
+	source := &MonotonicNonce{now: func() time.Time { return clock }}
+
+	first, _ := strconv.ParseInt(source.Nonce(), 10, 64)
+	clock = clock.Add(-time.Second)
+	second, _ := strconv.ParseInt(source.Nonce(), 10, 64)
+
+	assert.Equal(t, int64(1500000000000000), first)
+	assert.Equal(t, first+1, second)
+}
+
+func TestNewAPIClient_SharedNonce(t *testing.T) {
+	first, _ := NewAPIClient("key", "secret")
+	second, _ := NewAPIClient("key", "secret")
+	assert.Same(t, first.Nonce, second.Nonce)
+
+	a, _ := strconv.ParseInt(first.Nonce.Nonce(), 10, 64)
+	b, _ := strconv.ParseInt(second.Nonce.Nonce(), 10, 64)
+	assert.True(t, b > a)
+}
+
+func TestAPIClient_InjectedNonce(t *testing.T) {
+	client, _ := NewAPIClient("key", "secret")
+	client.Nonce = &CounterNonce{Start: 41}
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(BalancesEndpoint),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "42", req.Header.Get("X-SBTC-NONCE"))
+			assert.Equal(t, client.SignRequest("GET", "/api/v2/balances", "42"), req.Header.Get("X-SBTC-SIGNATURE"))
+			return httpmock.NewStringResponse(200, `{"balances": []}`), nil
+		})
+
+	_, err := client.GetBalances()
+	assert.NoError(t, err)
+}
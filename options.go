@@ -0,0 +1,95 @@
+package buda
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type Environment string
+
+const (
+	Production Environment = BaseURL
+	Staging    Environment = "https://stg.buda.com/api/v2"
+)
+
+const DefaultUserAgent = "go-buda"
+
+// Option configures an APIClient built by NewAPIClient.
+type Option func(*APIClient) error
+
+func WithBaseURL(baseURL string) Option {
+	return func(client *APIClient) error {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("base URL %q must be absolute", baseURL)
+		}
+
+		client.BaseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+func WithEnvironment(environment Environment) Option {
+	return WithBaseURL(string(environment))
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *APIClient) error {
+		if httpClient == nil {
+			return fmt.Errorf("http client must not be nil")
+		}
+
+		client.Client = httpClient
+		return nil
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(client *APIClient) error {
+		client.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout bounds every HTTP round trip. It applies to the client passed
+// to WithHTTPClient as well, without modifying the caller's copy.
+func WithTimeout(timeout time.Duration) Option {
+	return func(client *APIClient) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative")
+		}
+
+		client.timeout = timeout
+		return nil
+	}
+}
+
+func WithRateLimits(public *rate.Limiter, private *rate.Limiter) Option {
+	return func(client *APIClient) error {
+		client.SetRateLimits(public, private)
+		return nil
+	}
+}
+
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(client *APIClient) error {
+		client.Retry = policy
+		return nil
+	}
+}
+
+func WithNonceSource(source NonceSource) Option {
+	return func(client *APIClient) error {
+		client.Nonce = source
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package buda
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPIClient_Defaults(t *testing.T) {
+	client, err := NewAPIClient("key", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, BaseURL+MarketsEndpoint, client.FormatResource(MarketsEndpoint))
+	assert.Equal(t, DefaultUserAgent, client.UserAgent)
+	assert.Equal(t, time.Duration(0), client.Client.Timeout)
+}
+
+func TestNewAPIClient_Environment(t *testing.T) {
+	client, err := NewAPIClient("key", "secret", WithEnvironment(Staging))
+	assert.NoError(t, err)
+	assert.Equal(t, string(Staging)+BalancesEndpoint, client.FormatResource(BalancesEndpoint))
+}
+
+func TestNewAPIClient_InvalidOptions(t *testing.T) {
+	_, err := NewAPIClient("key", "secret", WithBaseURL("localhost"))
+	assert.Error(t, err)
+	_, err = NewAPIClient("key", "secret", WithHTTPClient(nil))
+	assert.Error(t, err)
+	_, err = NewAPIClient("key", "secret", WithTimeout(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestNewAPIClient_TimeoutDoesNotModifyCallerClient(t *testing.T) {
+	httpClient := &http.Client{}
+	client, err := NewAPIClient("key", "secret", WithHTTPClient(httpClient), WithTimeout(3*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, client.Client.Timeout)
+	assert.Equal(t, time.Duration(0), httpClient.Timeout)
+}
+
+func TestNewAPIClient_LocalServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2"+MarketsEndpoint, r.URL.Path)
+		assert.Equal(t, "bot/1.0", r.Header.Get("User-Agent"))
+		http.ServeFile(w, r, "fixtures/markets.json")
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient("key", "secret",
+		WithBaseURL(server.URL+"/api/v2/"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("bot/1.0"),
+		WithRetryPolicy(nil))
+	assert.NoError(t, err)
+
+	markets, err := client.GetMarkets()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, markets)
+}
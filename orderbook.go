@@ -0,0 +1,350 @@
+package buda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/btree"
+	"github.com/shopspring/decimal"
+)
+
+var ErrSyncInProgress = errors.New("order book sync already in progress")
+
+type PriceLevel struct {
+	Price  decimal.Decimal
+	Amount decimal.Decimal
+}
+
+func parseLevels(entries [][]string) ([]PriceLevel, error) {
+	levels := make([]PriceLevel, 0, len(entries))
+
+	for _, entry := range entries {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("order book level must be a [price, amount] pair")
+		}
+
+		price, err := decimal.NewFromString(entry[0])
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := decimal.NewFromString(entry[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if !price.IsPositive() || amount.IsNegative() {
+			return nil, fmt.Errorf("invalid order book level [%s, %s]", entry[0], entry[1])
+		}
+
+		levels = append(levels, PriceLevel{Price: price, Amount: amount})
+	}
+
+	return levels, nil
+}
+
+// LocalOrderBook keeps an order book up to date from incremental changes
+// instead of re-fetching it. Levels live in B-trees ordered best price first,
+// so every change costs O(log n). Whenever a change leaves the book crossed
+// or otherwise inconsistent it is resynced from GetOrderBookByMarket. Only
+// one sync runs at a time; changes that arrive meanwhile are buffered and
+// replayed in order over the fetched snapshot. Since a change sets the
+// absolute amount of a level, replaying one the snapshot already reflects is
+// harmless.
+type LocalOrderBook struct {
+	MarketId string
+
+	client     *APIClient
+	mutex      sync.RWMutex
+	bids       *btree.BTreeG[PriceLevel]
+	asks       *btree.BTreeG[PriceLevel]
+	synced     bool
+	syncing    bool
+	generation int
+	resyncs    int
+	pending    []*BookChange
+}
+
+func (client *APIClient) NewLocalOrderBook(marketId string) *LocalOrderBook {
+	return &LocalOrderBook{
+		MarketId: marketId,
+		client:   client,
+		bids:     newBookSide(BookSideBids),
+		asks:     newBookSide(BookSideAsks),
+	}
+}
+
+func newBookSide(side BookSide) *btree.BTreeG[PriceLevel] {
+	if side == BookSideBids {
+		return btree.NewG(32, func(a, b PriceLevel) bool { return a.Price.GreaterThan(b.Price) })
+	}
+	return btree.NewG(32, func(a, b PriceLevel) bool { return a.Price.LessThan(b.Price) })
+}
+
+// Sync reloads the book from GetOrderBookByMarket. It returns
+// ErrSyncInProgress when another sync is already running.
+func (book *LocalOrderBook) Sync(ctx context.Context) error {
+	book.mutex.Lock()
+	started := book.beginSync()
+	book.mutex.Unlock()
+
+	if !started {
+		return ErrSyncInProgress
+	}
+
+	return book.fetch(ctx)
+}
+
+func (book *LocalOrderBook) beginSync() bool {
+	if book.syncing {
+		return false
+	}
+	book.syncing = true
+	book.synced = false
+	book.pending = nil
+	return true
+}
+
+// fetch finishes a sync started with beginSync and replays the changes
+// buffered meanwhile. A snapshot applied while the request was in flight is
+// newer than the response, which is then discarded.
+func (book *LocalOrderBook) fetch(ctx context.Context) error {
+	book.mutex.RLock()
+	generation := book.generation
+	book.mutex.RUnlock()
+
+	snapshot, err := book.client.GetOrderBookByMarketWithContext(ctx, book.MarketId)
+
+	var bids, asks []PriceLevel
+	if err == nil {
+		bids, asks, err = parseSnapshot(snapshot)
+	}
+
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+
+	book.syncing = false
+	pending := book.pending
+	book.pending = nil
+
+	if err != nil {
+		return err
+	}
+
+	if book.generation == generation {
+		err = book.load(bids, asks)
+		if err != nil {
+			return err
+		}
+	}
+
+	return book.replay(pending)
+}
+
+func (book *LocalOrderBook) replay(pending []*BookChange) error {
+	if !book.synced {
+		return nil
+	}
+
+	for _, change := range pending {
+		if !book.apply(change) {
+			book.synced = false
+			return fmt.Errorf("%s %s change at %s leaves the %s order book inconsistent", change.Side, change.Amount, change.Price, book.MarketId)
+		}
+	}
+
+	return nil
+}
+
+func parseSnapshot(snapshot *OrderBook) ([]PriceLevel, []PriceLevel, error) {
+	bids, err := parseLevels(snapshot.Bids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	asks, err := parseLevels(snapshot.Asks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bids, asks, nil
+}
+
+// ApplySnapshot replaces every level, e.g. with the data of a BookSnapshot
+// event.
+func (book *LocalOrderBook) ApplySnapshot(snapshot *OrderBook) error {
+	bids, asks, err := parseSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	book.mutex.Lock()
+	defer book.mutex.Unlock()
+
+	book.pending = nil
+	return book.load(bids, asks)
+}
+
+func (book *LocalOrderBook) load(bids []PriceLevel, asks []PriceLevel) error {
+	book.generation++
+	book.bids, book.asks = newBookSide(BookSideBids), newBookSide(BookSideAsks)
+	for _, level := range bids {
+		book.set(book.bids, level)
+	}
+	for _, level := range asks {
+		book.set(book.asks, level)
+	}
+
+	book.synced = true
+	if book.crossed() {
+		book.synced = false
+		return fmt.Errorf("order book snapshot for %s is crossed", book.MarketId)
+	}
+
+	return nil
+}
+
+// Apply applies one level change. Changes that arrive before the first sync,
+// leave the book crossed or cannot be applied trigger a resync, whose error
+// is returned. Changes that arrive during a sync are buffered until it ends.
+func (book *LocalOrderBook) Apply(ctx context.Context, change *BookChange) error {
+	if change.MarketId != "" && change.MarketId != book.MarketId {
+		return fmt.Errorf("book change for %s applied to %s order book", change.MarketId, book.MarketId)
+	}
+
+	book.mutex.Lock()
+	if book.syncing {
+		if validChange(change) {
+			book.pending = append(book.pending, change)
+		}
+		book.mutex.Unlock()
+		return nil
+	}
+
+	synced := book.synced
+	if synced && book.apply(change) {
+		book.mutex.Unlock()
+		return nil
+	}
+
+	// A change that made a synced book inconsistent is superseded by the
+	// snapshot; one that merely arrived before the first sync is not.
+	book.beginSync()
+	book.resyncs++
+	if !synced && validChange(change) {
+		book.pending = append(book.pending, change)
+	}
+	book.mutex.Unlock()
+
+	return book.fetch(ctx)
+}
+
+func validChange(change *BookChange) bool {
+	if change.Side != BookSideBids && change.Side != BookSideAsks {
+		return false
+	}
+	return !change.Amount.IsNegative() && change.Price.IsPositive()
+}
+
+// apply writes a change unless it is invalid or would cross the book, so
+// readers never see a crossed state.
+func (book *LocalOrderBook) apply(change *BookChange) bool {
+	if !validChange(change) || book.wouldCross(change) {
+		return false
+	}
+
+	side := book.asks
+	if change.Side == BookSideBids {
+		side = book.bids
+	}
+
+	book.set(side, PriceLevel{Price: change.Price, Amount: change.Amount})
+	return true
+}
+
+func (book *LocalOrderBook) wouldCross(change *BookChange) bool {
+	if change.Amount.IsZero() {
+		return false
+	}
+
+	if change.Side == BookSideBids {
+		ask, ok := book.asks.Min()
+		return ok && change.Price.GreaterThanOrEqual(ask.Price)
+	}
+
+	bid, ok := book.bids.Min()
+	return ok && change.Price.LessThanOrEqual(bid.Price)
+}
+
+func (book *LocalOrderBook) set(side *btree.BTreeG[PriceLevel], level PriceLevel) {
+	if level.Amount.IsZero() {
+		side.Delete(level)
+		return
+	}
+	side.ReplaceOrInsert(level)
+}
+
+func (book *LocalOrderBook) crossed() bool {
+	bid, hasBid := book.bids.Min()
+	ask, hasAsk := book.asks.Min()
+	return hasBid && hasAsk && bid.Price.GreaterThanOrEqual(ask.Price)
+}
+
+// Resyncs counts the resyncs triggered by Apply.
+func (book *LocalOrderBook) Resyncs() int {
+	book.mutex.RLock()
+	defer book.mutex.RUnlock()
+	return book.resyncs
+}
+
+func (book *LocalOrderBook) Synced() bool {
+	book.mutex.RLock()
+	defer book.mutex.RUnlock()
+	return book.synced
+}
+
+// Bids returns up to depth levels, best first; a depth of 0 returns them all.
+func (book *LocalOrderBook) Bids(depth int) []PriceLevel {
+	book.mutex.RLock()
+	defer book.mutex.RUnlock()
+	return levels(book.bids, depth)
+}
+
+// Asks returns up to depth levels, best first; a depth of 0 returns them all.
+func (book *LocalOrderBook) Asks(depth int) []PriceLevel {
+	book.mutex.RLock()
+	defer book.mutex.RUnlock()
+	return levels(book.asks, depth)
+}
+
+func levels(side *btree.BTreeG[PriceLevel], depth int) []PriceLevel {
+	var result []PriceLevel
+
+	side.Ascend(func(level PriceLevel) bool {
+		result = append(result, level)
+		return depth <= 0 || len(result) < depth
+	})
+
+	return result
+}
+
+// Snapshot returns the book in the API's OrderBook format.
+func (book *LocalOrderBook) Snapshot() *OrderBook {
+	book.mutex.RLock()
+	defer book.mutex.RUnlock()
+
+	return &OrderBook{Asks: formatLevels(book.asks), Bids: formatLevels(book.bids)}
+}
+
+func formatLevels(side *btree.BTreeG[PriceLevel]) [][]string {
+	result := make([][]string, 0, side.Len())
+
+	side.Ascend(func(level PriceLevel) bool {
+		result = append(result, []string{level.Price.String(), level.Amount.String()})
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,152 @@
+package buda
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func bookChange(side BookSide, price string, amount string) *BookChange {
+	return &BookChange{
+		MarketId: "BTC-CLP",
+		Side:     side,
+		Price:    decimal.RequireFromString(price),
+		Amount:   decimal.RequireFromString(amount),
+	}
+}
+
+func TestLocalOrderBook_ApplyChanges(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockResponseFromFile(client.FormatResource(fmt.Sprintf(MarketOrderBookEndpoint, "BTC-CLP")), "fixtures/market_order_book.json")
+
+	book := client.NewLocalOrderBook("BTC-CLP")
+	assert.NoError(t, book.Sync(context.Background()))
+	assert.Len(t, book.Bids(0), 8)
+	assert.Len(t, book.Asks(3), 3)
+
+	ctx := context.Background()
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideBids, "822000", "0.5")))
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideAsks, "836677.14", "0")))
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideAsks, "837462.23", "2")))
+
+	bids := book.Bids(2)
+	assert.True(t, bids[0].Price.Equal(decimal.RequireFromString("822000")))
+	assert.True(t, bids[1].Price.Equal(decimal.RequireFromString("821580")))
+
+	asks := book.Asks(1)
+	assert.True(t, asks[0].Price.Equal(decimal.RequireFromString("837462.23")))
+	assert.True(t, asks[0].Amount.Equal(decimal.NewFromInt(2)))
+	assert.Len(t, book.Snapshot().Asks, 8)
+	assert.Equal(t, 0, book.Resyncs())
+
+	assert.Error(t, book.Apply(ctx, &BookChange{MarketId: "ETH-CLP"}))
+}
+
+func TestLocalOrderBook_ResyncsWhenInconsistent(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	resource := client.FormatResource(fmt.Sprintf(MarketOrderBookEndpoint, "BTC-CLP"))
+	mockResponseFromFile(resource, "fixtures/market_order_book.json")
+
+	ctx := context.Background()
+	book := client.NewLocalOrderBook("BTC-CLP")
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideBids, "822000", "0.5")))
+	assert.True(t, book.Synced())
+	assert.Equal(t, 1, book.Resyncs())
+	assert.True(t, book.Bids(1)[0].Price.Equal(decimal.RequireFromString("822000")))
+
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideBids, "840000", "1")))
+	assert.Equal(t, 2, book.Resyncs())
+	bids := book.Bids(1)
+	assert.True(t, bids[0].Price.Equal(decimal.RequireFromString("821580")))
+
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideAsks, "830000", "-1")))
+	assert.Equal(t, 3, book.Resyncs())
+	assert.Equal(t, 3, httpmock.GetCallCountInfo()["GET "+resource])
+
+	crossed := &OrderBook{Bids: [][]string{{"10", "1"}}, Asks: [][]string{{"9", "1"}}}
+	assert.Error(t, book.ApplySnapshot(crossed))
+	assert.False(t, book.Synced())
+}
+
+func TestLocalOrderBook_SingleResync(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	response, _ := ioutil.ReadFile("fixtures/market_order_book.json")
+	resource := client.FormatResource(fmt.Sprintf(MarketOrderBookEndpoint, "BTC-CLP"))
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	httpmock.RegisterResponder("GET", resource, func(req *http.Request) (*http.Response, error) {
+		entered <- struct{}{}
+		<-release
+		return httpmock.NewStringResponse(200, string(response)), nil
+	})
+
+	ctx := context.Background()
+	book := client.NewLocalOrderBook("BTC-CLP")
+	done := make(chan error)
+	go func() { done <- book.Apply(ctx, bookChange(BookSideBids, "822000", "0.5")) }()
+	<-entered
+
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideBids, "823000", "0.5")))
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideAsks, "830000", "-1")))
+	assert.Equal(t, ErrSyncInProgress, book.Sync(ctx))
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, book.Resyncs())
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+resource])
+
+	bids := book.Bids(2)
+	assert.True(t, bids[0].Price.Equal(decimal.RequireFromString("823000")))
+	assert.True(t, bids[1].Price.Equal(decimal.RequireFromString("822000")))
+}
+
+func TestLocalOrderBook_NeverCrossed(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	resource := client.FormatResource(fmt.Sprintf(MarketOrderBookEndpoint, "BTC-CLP"))
+	mockResponseFromFile(resource, "fixtures/market_order_book.json")
+
+	ctx := context.Background()
+	book := client.NewLocalOrderBook("BTC-CLP")
+	assert.NoError(t, book.Sync(ctx))
+
+	response, _ := ioutil.ReadFile("fixtures/market_order_book.json")
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	httpmock.RegisterResponder("GET", resource, func(req *http.Request) (*http.Response, error) {
+		entered <- struct{}{}
+		<-release
+		return httpmock.NewStringResponse(200, string(response)), nil
+	})
+
+	done := make(chan error)
+	go func() { done <- book.Apply(ctx, bookChange(BookSideBids, "840000", "1")) }()
+	<-entered
+
+	assert.True(t, book.Bids(1)[0].Price.Equal(decimal.RequireFromString("821580")))
+	assert.True(t, book.Asks(1)[0].Price.Equal(decimal.RequireFromString("836677.14")))
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideAsks, "830000", "1")))
+	assert.NoError(t, book.Apply(ctx, bookChange(BookSideBids, "829000", "1")))
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.True(t, book.Synced())
+	assert.True(t, book.Bids(1)[0].Price.Equal(decimal.RequireFromString("829000")))
+	assert.True(t, book.Asks(1)[0].Price.Equal(decimal.RequireFromString("830000")))
+	assert.Equal(t, 1, book.Resyncs())
+}
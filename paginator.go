@@ -0,0 +1,191 @@
+package buda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+var ErrNoMorePages = errors.New("no more pages")
+
+type pageFetcher[T any] func(ctx context.Context, page int) ([]T, Metadata, error)
+
+// Paginator walks a paginated listing either lazily, one page per call to
+// Next, or all at once with All.
+type Paginator[T any] struct {
+	fetch pageFetcher[T]
+	page  int
+	total int
+	err   error
+}
+
+func newPaginator[T any](fetch pageFetcher[T]) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch, page: 1, total: -1}
+}
+
+func failedPaginator[T any](err error) *Paginator[T] {
+	return &Paginator[T]{page: 1, total: -1, err: err}
+}
+
+// HasNext reports whether a call to Next may return more items. Before the
+// first page is fetched the number of pages is unknown, so it returns true.
+func (paginator *Paginator[T]) HasNext() bool {
+	return paginator.err != nil || paginator.total < 0 || paginator.page <= paginator.total
+}
+
+// Next fetches the following page. A failed page can be retried by calling
+// Next again.
+func (paginator *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if paginator.err != nil {
+		return nil, paginator.err
+	}
+
+	if !paginator.HasNext() {
+		return nil, ErrNoMorePages
+	}
+
+	items, meta, err := paginator.fetch(ctx, paginator.page)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator.total = meta.TotalPages
+	paginator.page++
+
+	return items, nil
+}
+
+// All collects every page not yet returned by Next, fetching up to
+// concurrency pages in parallel. Items keep the order of the pages they came
+// from; the first error cancels the pages still in flight.
+func (paginator *Paginator[T]) All(ctx context.Context, concurrency int) ([]T, error) {
+	var ret []T
+
+	if paginator.err != nil {
+		return nil, paginator.err
+	}
+
+	if paginator.total < 0 {
+		first, err := paginator.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, first...)
+	}
+
+	remaining := paginator.total - paginator.page + 1
+	if remaining <= 0 {
+		return ret, nil
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]T, remaining)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i := 0; i < remaining && fetchCtx.Err() == nil; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-fetchCtx.Done():
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			items, _, err := paginator.fetch(fetchCtx, paginator.page+i)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			pages[i] = items
+		}(i)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	paginator.page = paginator.total + 1
+	for _, page := range pages {
+		ret = append(ret, page...)
+	}
+
+	return ret, nil
+}
+
+func pageQuery(page int, state string) string {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per", ElementsPerPage)
+
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	return "?" + query.Encode()
+}
+
+// OrdersPaginator lists the orders of a market, optionally filtered by state.
+func (client *APIClient) OrdersPaginator(marketId string, state OrderState) *Paginator[Order] {
+	if state != "" && !state.Valid() {
+		return failedPaginator[Order](fmt.Errorf("invalid order state %q", state))
+	}
+
+	return newPaginator(func(ctx context.Context, page int) ([]Order, Metadata, error) {
+		var orders Orders
+
+		err := client.getPage(ctx, fmt.Sprintf(OrdersEndpoint, marketId)+pageQuery(page, string(state)), &orders)
+		return orders.Orders, orders.Meta, err
+	})
+}
+
+// DepositsPaginator lists the deposits of a currency, optionally filtered by state.
+func (client *APIClient) DepositsPaginator(currency string, state DepositState) *Paginator[Deposit] {
+	if state != "" && !state.Valid() {
+		return failedPaginator[Deposit](fmt.Errorf("invalid deposit state %q", state))
+	}
+
+	return newPaginator(func(ctx context.Context, page int) ([]Deposit, Metadata, error) {
+		var deposits Deposits
+
+		err := client.getPage(ctx, fmt.Sprintf(DepositsEndpoint, currency)+pageQuery(page, string(state)), &deposits)
+		return deposits.Deposits, deposits.Meta, err
+	})
+}
+
+// WithdrawalsPaginator lists the withdrawals of a currency, optionally filtered by state.
+func (client *APIClient) WithdrawalsPaginator(currency string, state WithdrawalState) *Paginator[Withdrawal] {
+	if state != "" && !state.Valid() {
+		return failedPaginator[Withdrawal](fmt.Errorf("invalid withdrawal state %q", state))
+	}
+
+	return newPaginator(func(ctx context.Context, page int) ([]Withdrawal, Metadata, error) {
+		var withdrawals Withdrawals
+
+		err := client.getPage(ctx, fmt.Sprintf(WithdrawalsEndpoint, currency)+pageQuery(page, string(state)), &withdrawals)
+		return withdrawals.Withdrawals, withdrawals.Meta, err
+	})
+}
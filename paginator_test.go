@@ -0,0 +1,116 @@
+package buda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockOrderPages(client *APIClient, pages int, failPage int) *int32 {
+	var inFlight, maxInFlight int32
+	var mutex sync.Mutex
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(OrdersEndpoint, "BTC-CLP")),
+		func(req *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mutex.Unlock()
+			defer func() {
+				mutex.Lock()
+				inFlight--
+				mutex.Unlock()
+			}()
+
+			page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+			// later pages answer first to shake out ordering bugs
+			time.Sleep(time.Duration(pages-page) * time.Millisecond)
+			if page == failPage {
+				return httpmock.NewStringResponse(500, `{"message": "boom"}`), nil
+			}
+			body := fmt.Sprintf(`{"orders": [{"id": %d}, {"id": %d}], "meta": {"current_page": %d, "total_count": %d, "total_pages": %d}}`,
+				page*2-1, page*2, page, pages*2, pages)
+			return httpmock.NewStringResponse(200, body), nil
+		})
+	return &maxInFlight
+}
+
+func TestPaginator_AllKeepsPageOrder(t *testing.T) {
+	client, _ := NewAPIClient("", "", WithRetryPolicy(nil))
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	maxInFlight := mockOrderPages(client, 10, 0)
+
+	orders, err := client.OrdersPaginator("BTC-CLP", "").All(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 20)
+	for i, order := range orders {
+		assert.Equal(t, i+1, order.ID)
+	}
+	assert.True(t, *maxInFlight <= 3)
+}
+
+func TestPaginator_AllStopsOnError(t *testing.T) {
+	client, _ := NewAPIClient("", "", WithRetryPolicy(nil))
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockOrderPages(client, 10, 4)
+
+	orders, err := client.GetOrdersByMarket("BTC-CLP")
+	assert.Nil(t, orders)
+	assert.Equal(t, 500, err.(*APIError).StatusCode)
+}
+
+func TestPaginator_Next(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockOrderPages(client, 3, 0)
+
+	paginator := client.OrdersPaginator("BTC-CLP", OrderStatePending)
+	var ids []int
+	for paginator.HasNext() {
+		orders, err := paginator.Next(context.Background())
+		assert.NoError(t, err)
+		for _, order := range orders {
+			ids = append(ids, order.ID)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, ids)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	_, err := paginator.Next(context.Background())
+	assert.Equal(t, ErrNoMorePages, err)
+}
+
+func TestPaginator_NextThenAll(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockOrderPages(client, 3, 0)
+
+	paginator := client.OrdersPaginator("BTC-CLP", "")
+	first, err := paginator.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+
+	rest, err := paginator.All(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rest[0].ID)
+	assert.Len(t, rest, 4)
+	assert.False(t, paginator.HasNext())
+}
+
+func TestPaginator_InvalidState(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	_, err := client.DepositsPaginator("BTC", DepositState("nope")).All(context.Background(), 1)
+	assert.Error(t, err)
+}
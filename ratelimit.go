@@ -0,0 +1,34 @@
+package buda
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// Default budgets follow the limits published by Buda: public endpoints are
+// throttled per IP, private ones per API key.
+const (
+	DefaultPublicRate   = rate.Limit(20)
+	DefaultPublicBurst  = 20
+	DefaultPrivateRate  = rate.Limit(375.0 / 60.0)
+	DefaultPrivateBurst = 10
+)
+
+func (client *APIClient) SetRateLimits(public *rate.Limiter, private *rate.Limiter) {
+	client.PublicLimiter = public
+	client.PrivateLimiter = private
+}
+
+func (client *APIClient) wait(ctx context.Context, private bool) error {
+	limiter := client.PublicLimiter
+	if private {
+		limiter = client.PrivateLimiter
+	}
+
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,45 @@
+package buda
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestAPIClient_RateLimit(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	client.SetRateLimits(rate.NewLimiter(rate.Every(50*time.Millisecond), 1), nil)
+	mockResponseFromFile(client.FormatResource(MarketsEndpoint), "fixtures/markets.json")
+	defer httpmock.DeactivateAndReset()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetMarkets()
+		assert.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+}
+
+func TestAPIClient_RateLimitSeparateBudgets(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	client.SetRateLimits(rate.NewLimiter(rate.Inf, 1), rate.NewLimiter(rate.Every(time.Hour), 1))
+	mockResponseFromFile(client.FormatResource(BalancesEndpoint), "fixtures/balances.json")
+	mockResponseFromFile(client.FormatResource(MarketsEndpoint), "fixtures/markets.json")
+	defer httpmock.DeactivateAndReset()
+
+	_, err := client.GetBalances()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetBalancesWithContext(ctx)
+	assert.Error(t, err)
+
+	_, err = client.GetMarkets()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,490 @@
+package buda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// ErrAlreadyRun is returned by Run when the feed has already been run; its
+// events channel is closed once the first Run returns.
+var ErrAlreadyRun = errors.New("feed can only be run once")
+
+// PusherURL is the websocket endpoint for a Pusher application key.
+const PusherURL = "wss://ws.pusherapp.com/app/%s?protocol=7&client=go-buda&version=2.0"
+
+const (
+	pusherConnectionEstablished = "pusher:connection_established"
+	pusherSubscribe             = "pusher:subscribe"
+	pusherSubscriptionSucceeded = "pusher_internal:subscription_succeeded"
+	pusherError                 = "pusher:error"
+	pusherPing                  = "pusher:ping"
+	pusherPong                  = "pusher:pong"
+
+	BookSyncEvent    = "book-sync"
+	BookChangedEvent = "book-changed"
+	NewTradeEvent    = "new-trade"
+)
+
+const (
+	DefaultActivityTimeout = 120 * time.Second
+	DefaultPongTimeout     = 30 * time.Second
+	DefaultReconnectMin    = time.Second
+	DefaultReconnectMax    = 30 * time.Second
+)
+
+// Event is anything delivered by Realtime.Events: *ConnectedEvent,
+// *ErrorEvent, *BookSnapshot, *BookChange, *TradeEvent or one of the account
+// events.
+type Event interface {
+	Channel() string
+}
+
+// ConnectedEvent is emitted every time the socket (re)connects. Anything
+// received before it may have missed updates and should be resynced.
+type ConnectedEvent struct {
+	SocketID string
+}
+
+// ErrorEvent reports a message that could not be decoded. The connection is
+// dropped right after it, so the ConnectedEvent that follows tells consumers
+// to resync whatever the lost message would have updated.
+type ErrorEvent struct {
+	Err     error
+	channel string
+}
+
+type BookSnapshot struct {
+	MarketId  string
+	OrderBook OrderBook
+	channel   string
+}
+
+// BookChange sets the amount resting at one price level; a zero amount
+// removes the level.
+type BookChange struct {
+	MarketId string
+	Side     BookSide
+	Price    decimal.Decimal
+	Amount   decimal.Decimal
+	channel  string
+}
+
+type TradeEvent struct {
+	MarketId string
+	Trade    TradeEntry
+	channel  string
+}
+
+func (event *ConnectedEvent) Channel() string { return "" }
+func (event *ErrorEvent) Channel() string     { return event.channel }
+func (event *BookSnapshot) Channel() string   { return event.channel }
+func (event *BookChange) Channel() string     { return event.channel }
+func (event *TradeEvent) Channel() string     { return event.channel }
+
+type BookSide string
+
+const (
+	BookSideBids BookSide = "bids"
+	BookSideAsks BookSide = "asks"
+)
+
+func channelSuffix(marketId string) string {
+	return strings.ToLower(strings.Replace(marketId, "-", "", -1))
+}
+
+func BookChannel(marketId string) string {
+	return "book@" + channelSuffix(marketId)
+}
+
+func TradesChannel(marketId string) string {
+	return "trades@" + channelSuffix(marketId)
+}
+
+type pusherMessage struct {
+	Event   string          `json:"event"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// payload unwraps event data, which Pusher usually sends as a JSON encoded
+// string rather than an object.
+func (message *pusherMessage) payload() []byte {
+	data := bytes.TrimSpace(message.Data)
+	if len(data) > 0 && data[0] == '"' {
+		var inner string
+		if json.Unmarshal(data, &inner) == nil {
+			return []byte(inner)
+		}
+	}
+	return data
+}
+
+type subscription struct {
+	marketId  string
+	decode    func(channel string, marketId string, message *pusherMessage) (Event, error)
+	authorize ChannelAuthorizer
+}
+
+// Realtime keeps a websocket connection to Buda's Pusher application,
+// reconnecting with backoff and resubscribing every channel after each
+// reconnect. Timeouts that are not positive fall back to their defaults, and
+// an unset ActivityTimeout defers to the one announced by the server.
+type Realtime struct {
+	URL             string
+	Dialer          *websocket.Dialer
+	ActivityTimeout time.Duration
+	PongTimeout     time.Duration
+	ReconnectMin    time.Duration
+	ReconnectMax    time.Duration
+
+	events        chan Event
+	mutex         sync.Mutex
+	writeMutex    sync.Mutex
+	conn          *websocket.Conn
+	socketID      string
+	subscriptions map[string]subscription
+	started       bool
+}
+
+func NewRealtime(url string) *Realtime {
+	return &Realtime{
+		URL:             url,
+		Dialer:          websocket.DefaultDialer,
+		ActivityTimeout: DefaultActivityTimeout,
+		PongTimeout:     DefaultPongTimeout,
+		ReconnectMin:    DefaultReconnectMin,
+		ReconnectMax:    DefaultReconnectMax,
+		events:          make(chan Event),
+		subscriptions:   make(map[string]subscription),
+	}
+}
+
+// Events is closed when Run returns.
+func (realtime *Realtime) Events() <-chan Event {
+	return realtime.events
+}
+
+func (realtime *Realtime) SubscribeBook(marketId string) error {
+	return realtime.subscribe(context.Background(), BookChannel(marketId), subscription{marketId: marketId, decode: decodeBookEvent})
+}
+
+func (realtime *Realtime) SubscribeTrades(marketId string) error {
+	return realtime.subscribe(context.Background(), TradesChannel(marketId), subscription{marketId: marketId, decode: decodeTradeEvent})
+}
+
+func (realtime *Realtime) subscribe(ctx context.Context, channel string, sub subscription) error {
+	realtime.mutex.Lock()
+	realtime.subscriptions[channel] = sub
+	conn, socketID := realtime.conn, realtime.socketID
+	realtime.mutex.Unlock()
+
+	if conn == nil || socketID == "" {
+		return nil
+	}
+
+	return realtime.sendSubscribe(ctx, conn, socketID, channel, sub)
+}
+
+func (realtime *Realtime) sendSubscribe(ctx context.Context, conn *websocket.Conn, socketID string, channel string, sub subscription) error {
+	request := map[string]string{"channel": channel}
+
+	if sub.authorize != nil {
+		auth, err := sub.authorize(ctx, socketID, channel)
+		if err != nil {
+			return err
+		}
+		request["auth"] = auth
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return realtime.write(conn, &pusherMessage{Event: pusherSubscribe, Data: data})
+}
+
+func (realtime *Realtime) write(conn *websocket.Conn, message *pusherMessage) error {
+	realtime.writeMutex.Lock()
+	defer realtime.writeMutex.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(durationOr(realtime.PongTimeout, DefaultPongTimeout)))
+	return conn.WriteJSON(message)
+}
+
+// Run connects and delivers events until ctx is done, reconnecting whenever
+// the connection drops. It can only be called once.
+func (realtime *Realtime) Run(ctx context.Context) error {
+	realtime.mutex.Lock()
+	started := realtime.started
+	realtime.started = true
+	realtime.mutex.Unlock()
+
+	if started {
+		return ErrAlreadyRun
+	}
+	defer close(realtime.events)
+
+	reconnectMin := durationOr(realtime.ReconnectMin, DefaultReconnectMin)
+	reconnectMax := realtime.ReconnectMax
+	if reconnectMax < reconnectMin {
+		reconnectMax = reconnectMin
+	}
+
+	delay := reconnectMin
+	for {
+		connected, err := realtime.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if connected {
+			delay = reconnectMin
+		} else if err != nil {
+			delay *= 2
+			if delay > reconnectMax {
+				delay = reconnectMax
+			}
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+		select {
+		case <-time.After(delay/2 + jitter):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// session runs a single connection and reports whether it got as far as the
+// connection_established handshake.
+func (realtime *Realtime) session(ctx context.Context) (bool, error) {
+	conn, _, err := realtime.Dialer.DialContext(ctx, realtime.URL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		realtime.mutex.Lock()
+		realtime.conn, realtime.socketID = nil, ""
+		realtime.mutex.Unlock()
+		conn.Close()
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	realtime.mutex.Lock()
+	realtime.conn = conn
+	realtime.mutex.Unlock()
+
+	connected := false
+	activity := durationOr(realtime.ActivityTimeout, DefaultActivityTimeout)
+	pong := durationOr(realtime.PongTimeout, DefaultPongTimeout)
+	for {
+		conn.SetReadDeadline(time.Now().Add(activity + pong))
+
+		var message pusherMessage
+		err := conn.ReadJSON(&message)
+		if err != nil {
+			return connected, err
+		}
+
+		switch message.Event {
+		case pusherConnectionEstablished:
+			activity, err = realtime.established(ctx, conn, &message, done)
+			if err != nil {
+				return connected, err
+			}
+			connected = true
+
+		case pusherPing:
+			err = realtime.write(conn, &pusherMessage{Event: pusherPong, Data: json.RawMessage("{}")})
+			if err != nil {
+				return connected, err
+			}
+
+		case pusherError:
+			return connected, fmt.Errorf("pusher error: %s", message.payload())
+
+		case pusherPong, pusherSubscriptionSucceeded:
+
+		default:
+			event, err := realtime.decode(&message)
+			if err != nil {
+				err = fmt.Errorf("decoding %s on %s: %v", message.Event, message.Channel, err)
+				event = &ErrorEvent{Err: err, channel: message.Channel}
+			}
+			if event == nil {
+				continue
+			}
+
+			select {
+			case realtime.events <- event:
+			case <-ctx.Done():
+				return connected, ctx.Err()
+			}
+
+			if err != nil {
+				return connected, err
+			}
+		}
+	}
+}
+
+// established handles the handshake: it resubscribes every channel and
+// returns the activity timeout negotiated with the server.
+func (realtime *Realtime) established(ctx context.Context, conn *websocket.Conn, message *pusherMessage, done chan struct{}) (time.Duration, error) {
+	activity := durationOr(realtime.ActivityTimeout, DefaultActivityTimeout)
+
+	var handshake struct {
+		SocketID        string `json:"socket_id"`
+		ActivityTimeout int    `json:"activity_timeout"`
+	}
+
+	err := json.Unmarshal(message.payload(), &handshake)
+	if err != nil {
+		return activity, err
+	}
+
+	if handshake.ActivityTimeout > 0 {
+		timeout := time.Duration(handshake.ActivityTimeout) * time.Second
+		if realtime.ActivityTimeout <= 0 || timeout < activity {
+			activity = timeout
+		}
+	}
+
+	realtime.mutex.Lock()
+	realtime.socketID = handshake.SocketID
+	subscriptions := make(map[string]subscription, len(realtime.subscriptions))
+	for channel, sub := range realtime.subscriptions {
+		subscriptions[channel] = sub
+	}
+	realtime.mutex.Unlock()
+
+	for channel, sub := range subscriptions {
+		err = realtime.sendSubscribe(ctx, conn, handshake.SocketID, channel, sub)
+		if err != nil {
+			return activity, err
+		}
+	}
+
+	go realtime.keepalive(conn, activity, done)
+
+	select {
+	case realtime.events <- &ConnectedEvent{SocketID: handshake.SocketID}:
+		return activity, nil
+	case <-ctx.Done():
+		return activity, ctx.Err()
+	}
+}
+
+// durationOr stands in fallback for timeouts left unset or set to a
+// non-positive value.
+func durationOr(value time.Duration, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func (realtime *Realtime) keepalive(conn *websocket.Conn, activity time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(activity)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if realtime.write(conn, &pusherMessage{Event: pusherPing, Data: json.RawMessage("{}")}) != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (realtime *Realtime) decode(message *pusherMessage) (Event, error) {
+	realtime.mutex.Lock()
+	sub, ok := realtime.subscriptions[message.Channel]
+	realtime.mutex.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return sub.decode(message.Channel, sub.marketId, message)
+}
+
+func decodeBookEvent(channel string, marketId string, message *pusherMessage) (Event, error) {
+	switch message.Event {
+	case BookSyncEvent:
+		snapshot := &BookSnapshot{MarketId: marketId, channel: channel}
+		err := json.Unmarshal(message.payload(), &snapshot.OrderBook)
+		if err != nil {
+			return nil, err
+		}
+		return snapshot, nil
+
+	case BookChangedEvent:
+		var raw struct {
+			Side   BookSide `json:"side"`
+			Change []string `json:"change"`
+		}
+
+		err := json.Unmarshal(message.payload(), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if raw.Side != BookSideBids && raw.Side != BookSideAsks {
+			return nil, fmt.Errorf("invalid book side %q", raw.Side)
+		}
+
+		if len(raw.Change) != 2 {
+			return nil, fmt.Errorf("book change must be a [price, amount] pair")
+		}
+
+		change := &BookChange{MarketId: marketId, Side: raw.Side, channel: channel}
+		change.Price, err = decimal.NewFromString(raw.Change[0])
+		if err != nil {
+			return nil, err
+		}
+		change.Amount, err = decimal.NewFromString(raw.Change[1])
+		if err != nil {
+			return nil, err
+		}
+		return change, nil
+	}
+
+	return nil, nil
+}
+
+func decodeTradeEvent(channel string, marketId string, message *pusherMessage) (Event, error) {
+	if message.Event != NewTradeEvent {
+		return nil, nil
+	}
+
+	event := &TradeEvent{MarketId: marketId, channel: channel}
+	err := json.Unmarshal(message.payload(), &event.Trade)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,219 @@
+package buda
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// pusherStandIn is a minimal Pusher server: it greets each connection,
+// records subscriptions and lets the test push events or drop the socket.
+type pusherStandIn struct {
+	server        *httptest.Server
+	mutex         sync.Mutex
+	conn          *websocket.Conn
+	connections   int
+	subscriptions chan map[string]interface{}
+}
+
+func newPusherStandIn() *pusherStandIn {
+	standIn := &pusherStandIn{subscriptions: make(chan map[string]interface{}, 16)}
+	upgrader := websocket.Upgrader{}
+
+	standIn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		standIn.mutex.Lock()
+		standIn.conn = conn
+		standIn.connections++
+		standIn.mutex.Unlock()
+
+		standIn.send(map[string]interface{}{
+			"event": "pusher:connection_established",
+			"data":  `{"socket_id": "123.456", "activity_timeout": 120}`,
+		})
+
+		for {
+			var message struct {
+				Event string                 `json:"event"`
+				Data  map[string]interface{} `json:"data"`
+			}
+			if conn.ReadJSON(&message) != nil {
+				return
+			}
+			if message.Event == "pusher:subscribe" {
+				standIn.send(map[string]interface{}{
+					"event":   "pusher_internal:subscription_succeeded",
+					"channel": message.Data["channel"],
+					"data":    "{}",
+				})
+				standIn.subscriptions <- message.Data
+			}
+		}
+	}))
+
+	return standIn
+}
+
+func (standIn *pusherStandIn) url() string {
+	return "ws" + strings.TrimPrefix(standIn.server.URL, "http")
+}
+
+func (standIn *pusherStandIn) send(message map[string]interface{}) {
+	standIn.mutex.Lock()
+	defer standIn.mutex.Unlock()
+	standIn.conn.WriteJSON(message)
+}
+
+func (standIn *pusherStandIn) publish(channel string, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	standIn.send(map[string]interface{}{"event": event, "channel": channel, "data": string(payload)})
+}
+
+func (standIn *pusherStandIn) drop() {
+	standIn.mutex.Lock()
+	defer standIn.mutex.Unlock()
+	standIn.conn.Close()
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return nil
+}
+
+func nextSubscription(t *testing.T, standIn *pusherStandIn) map[string]interface{} {
+	select {
+	case subscription := <-standIn.subscriptions:
+		return subscription
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for subscription")
+	}
+	return nil
+}
+
+func TestRealtime_BookAndTrades(t *testing.T) {
+	standIn := newPusherStandIn()
+	defer standIn.server.Close()
+
+	realtime := NewRealtime(standIn.url())
+	assert.NoError(t, realtime.SubscribeBook("BTC-CLP"))
+	assert.NoError(t, realtime.SubscribeTrades("BTC-CLP"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- realtime.Run(ctx) }()
+
+	connected := nextEvent(t, realtime.Events()).(*ConnectedEvent)
+	assert.Equal(t, "123.456", connected.SocketID)
+	channels := []interface{}{nextSubscription(t, standIn)["channel"], nextSubscription(t, standIn)["channel"]}
+	assert.ElementsMatch(t, []interface{}{"book@btcclp", "trades@btcclp"}, channels)
+
+	standIn.publish("book@btcclp", BookSyncEvent, map[string]interface{}{
+		"bids": [][]string{{"821580.0", "0.25"}},
+		"asks": [][]string{{"836677.14", "0.44"}},
+	})
+	snapshot := nextEvent(t, realtime.Events()).(*BookSnapshot)
+	assert.Equal(t, "BTC-CLP", snapshot.MarketId)
+	assert.Equal(t, [][]string{{"821580.0", "0.25"}}, snapshot.OrderBook.Bids)
+
+	standIn.publish("book@btcclp", BookChangedEvent, map[string]interface{}{"side": "asks", "change": []string{"836677.14", "0"}})
+	change := nextEvent(t, realtime.Events()).(*BookChange)
+	assert.Equal(t, BookSideAsks, change.Side)
+	assert.True(t, change.Price.Equal(decimal.RequireFromString("836677.14")))
+	assert.True(t, change.Amount.IsZero())
+
+	standIn.publish("trades@btcclp", NewTradeEvent, []interface{}{"1476905551687", "0.1", "435447.12", "buy", 77})
+	trade := nextEvent(t, realtime.Events()).(*TradeEvent)
+	assert.Equal(t, "trades@btcclp", trade.Channel())
+	assert.Equal(t, int64(77), trade.Trade.ID)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	_, open := <-realtime.Events()
+	assert.False(t, open)
+	assert.Equal(t, ErrAlreadyRun, realtime.Run(context.Background()))
+}
+
+func TestRealtime_ReconnectResubscribes(t *testing.T) {
+	standIn := newPusherStandIn()
+	defer standIn.server.Close()
+
+	realtime := NewRealtime(standIn.url())
+	realtime.ReconnectMin = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go realtime.Run(ctx)
+
+	nextEvent(t, realtime.Events())
+	assert.NoError(t, realtime.SubscribeTrades("ETH-BTC"))
+	assert.Equal(t, "trades@ethbtc", nextSubscription(t, standIn)["channel"])
+
+	standIn.drop()
+
+	assert.IsType(t, &ConnectedEvent{}, nextEvent(t, realtime.Events()))
+	assert.Equal(t, "trades@ethbtc", nextSubscription(t, standIn)["channel"])
+
+	standIn.publish("trades@ethbtc", NewTradeEvent, []interface{}{"1476905551687", "0.1", "0.05", "sell"})
+	trade := nextEvent(t, realtime.Events()).(*TradeEvent)
+	assert.Equal(t, "ETH-BTC", trade.MarketId)
+	assert.Equal(t, TradeDirectionSell, trade.Trade.Direction)
+}
+
+func TestRealtime_DecodeErrorReconnects(t *testing.T) {
+	standIn := newPusherStandIn()
+	defer standIn.server.Close()
+
+	realtime := NewRealtime(standIn.url())
+	realtime.ReconnectMin = 10 * time.Millisecond
+	assert.NoError(t, realtime.SubscribeBook("BTC-CLP"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go realtime.Run(ctx)
+
+	nextEvent(t, realtime.Events())
+	nextSubscription(t, standIn)
+
+	standIn.publish("book@btcclp", BookChangedEvent, map[string]interface{}{"side": "asks", "change": []string{"oops", "1"}})
+	failure := nextEvent(t, realtime.Events()).(*ErrorEvent)
+	assert.Equal(t, "book@btcclp", failure.Channel())
+	assert.Error(t, failure.Err)
+
+	assert.IsType(t, &ConnectedEvent{}, nextEvent(t, realtime.Events()))
+	assert.Equal(t, "book@btcclp", nextSubscription(t, standIn)["channel"])
+}
+
+func TestRealtime_NonPositiveTimeouts(t *testing.T) {
+	standIn := newPusherStandIn()
+	defer standIn.server.Close()
+
+	realtime := NewRealtime(standIn.url())
+	realtime.ActivityTimeout = 0
+	realtime.PongTimeout = -1
+	realtime.ReconnectMin = 0
+	realtime.ReconnectMax = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go realtime.Run(ctx)
+
+	assert.IsType(t, &ConnectedEvent{}, nextEvent(t, realtime.Events()))
+	assert.NoError(t, realtime.SubscribeTrades("BTC-CLP"))
+	assert.Equal(t, "trades@btcclp", nextSubscription(t, standIn)["channel"])
+}
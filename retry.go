@@ -0,0 +1,100 @@
+package buda
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. GETs are always
+// eligible; mutating calls only when their context was marked with Idempotent.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+type idempotentKey struct{}
+
+// Idempotent marks every request made with the returned context as safe to
+// retry, regardless of its HTTP method.
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func isIdempotent(ctx context.Context, method string) bool {
+	if method == "GET" {
+		return true
+	}
+	marked, _ := ctx.Value(idempotentKey{}).(bool)
+	return marked
+}
+
+func (policy *RetryPolicy) attempts(ctx context.Context, method string) int {
+	if policy == nil || policy.MaxAttempts < 1 || !isIdempotent(ctx, method) {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+// backoff returns the delay before the given retry, using exponential backoff
+// with full jitter unless the server asked for a specific delay.
+func (policy *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	ceiling := policy.MinBackoff << uint(attempt-1)
+	if ceiling <= 0 || ceiling > policy.MaxBackoff {
+		ceiling = policy.MaxBackoff
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func retryable(ctx context.Context, err error) (bool, time.Duration) {
+	if ctx.Err() != nil {
+		return false, 0
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		if apiError.StatusCode == http.StatusTooManyRequests || apiError.StatusCode >= 500 {
+			return true, apiError.RetryAfter
+		}
+		return false, 0
+	}
+
+	return true, 0
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
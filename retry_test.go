@@ -0,0 +1,125 @@
+package buda
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryingClient() *APIClient {
+	client, _ := NewAPIClient("key", "secret")
+	client.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	return client
+}
+
+func TestAPIClient_RetryTransientErrors(t *testing.T) {
+	client := newRetryingClient()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	response, _ := ioutil.ReadFile("fixtures/balances.json")
+	var nonces []string
+	httpmock.RegisterResponder("GET", client.FormatResource(BalancesEndpoint),
+		func(req *http.Request) (*http.Response, error) {
+			nonces = append(nonces, req.Header.Get("X-SBTC-NONCE"))
+			switch len(nonces) {
+			case 1:
+				return nil, fmt.Errorf("connection reset by peer")
+			case 2:
+				return httpmock.NewStringResponse(503, `{"message": "unavailable"}`), nil
+			}
+			return httpmock.NewStringResponse(200, string(response)), nil
+		})
+
+	balances, err := client.GetBalances()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, balances)
+	assert.Len(t, nonces, 3)
+	assert.NotEqual(t, nonces[0], nonces[1])
+	assert.NotEqual(t, nonces[1], nonces[2])
+}
+
+func TestAPIClient_RetryGivesUp(t *testing.T) {
+	client := newRetryingClient()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(MarketsEndpoint),
+		httpmock.NewStringResponder(502, "bad gateway"))
+
+	_, err := client.GetMarkets()
+	assert.Equal(t, 502, err.(*APIError).StatusCode)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestAPIClient_NoRetryOnClientErrors(t *testing.T) {
+	client := newRetryingClient()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", client.FormatResource(MarketsEndpoint),
+		httpmock.NewStringResponder(404, `{"code": "not_found"}`))
+
+	_, err := client.GetMarkets()
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestAPIClient_RetryMutatingOnlyWhenIdempotent(t *testing.T) {
+	client := newRetryingClient()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", client.FormatResource("/resource"),
+		httpmock.NewStringResponder(500, "oops"))
+
+	_, err := client.Post("/resource", nil, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	_, err = client.PostWithContext(Idempotent(context.Background()), "/resource", &OrderStateRequest{State: OrderStateCanceling}, true)
+	assert.Error(t, err)
+	assert.Equal(t, 4, httpmock.GetTotalCallCount())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, MinBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		delay := policy.backoff(attempt, 0)
+		assert.True(t, delay >= 0)
+		assert.True(t, delay <= 40*time.Millisecond)
+	}
+
+	assert.Equal(t, 2*time.Second, policy.backoff(1, 2*time.Second))
+}
+
+func TestNewAPIClient_OwnRetryPolicy(t *testing.T) {
+	first, _ := NewAPIClient("", "")
+	first.Retry.MaxAttempts = 1
+
+	second, _ := NewAPIClient("", "")
+	assert.Equal(t, 3, second.Retry.MaxAttempts)
+	assert.Equal(t, 3, DefaultRetryPolicy.MaxAttempts)
+}
+
+func TestRetryable_WrappedAPIError(t *testing.T) {
+	notFound := fmt.Errorf("fetching order: %w", &APIError{StatusCode: http.StatusNotFound})
+	retry, _ := retryable(context.Background(), notFound)
+	assert.False(t, retry)
+
+	limited := fmt.Errorf("fetching order: %w", &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second})
+	retry, delay := retryable(context.Background(), limited)
+	assert.True(t, retry)
+	assert.Equal(t, time.Second, delay)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	assert.True(t, parseRetryAfter(future) > 50*time.Second)
+}
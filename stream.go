@@ -0,0 +1,186 @@
+package buda
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is used by pollers created with a non-positive
+// interval.
+const DefaultPollInterval = 5 * time.Second
+
+type StreamTrade struct {
+	MarketId string
+	TradeEntry
+}
+
+type tradeCursor struct {
+	timestamp time.Time
+	seen      map[string]bool
+}
+
+// TradeStream polls the trades of one or more markets and delivers every new
+// trade exactly once, oldest first, on Trades. Polling errors are sent on
+// Errors when there is room and dropped otherwise; the stream keeps going.
+// Both channels are closed once the context passed to NewTradeStream ends.
+type TradeStream struct {
+	Trades <-chan StreamTrade
+	Errors <-chan error
+
+	client   *APIClient
+	markets  []string
+	interval time.Duration
+	mutex    sync.Mutex
+	cursors  map[string]*tradeCursor
+}
+
+// NewTradeStream starts polling right away. The first poll of each market
+// only records the latest trade; trades are emitted from then on.
+func (client *APIClient) NewTradeStream(ctx context.Context, interval time.Duration, markets ...string) *TradeStream {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	trades := make(chan StreamTrade)
+	errors := make(chan error, 1)
+
+	stream := &TradeStream{
+		Trades:   trades,
+		Errors:   errors,
+		client:   client,
+		markets:  markets,
+		interval: interval,
+		cursors:  make(map[string]*tradeCursor),
+	}
+
+	go stream.run(ctx, trades, errors)
+
+	return stream
+}
+
+// Cursor returns the timestamp of the latest trade seen for a market.
+func (stream *TradeStream) Cursor(marketId string) time.Time {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	if cursor, ok := stream.cursors[marketId]; ok {
+		return cursor.timestamp
+	}
+	return time.Time{}
+}
+
+func (stream *TradeStream) run(ctx context.Context, trades chan<- StreamTrade, errors chan<- error) {
+	defer close(trades)
+	defer close(errors)
+
+	ticker := time.NewTicker(stream.interval)
+	defer ticker.Stop()
+
+	for {
+		var batch []StreamTrade
+
+		for _, marketId := range stream.markets {
+			entries, err := stream.poll(ctx, marketId)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case errors <- err:
+				default:
+				}
+				continue
+			}
+
+			for _, entry := range entries {
+				batch = append(batch, StreamTrade{MarketId: marketId, TradeEntry: entry})
+			}
+		}
+
+		sort.SliceStable(batch, func(i, j int) bool {
+			return batch[i].Timestamp.Before(batch[j].Timestamp)
+		})
+
+		for _, trade := range batch {
+			select {
+			case trades <- trade:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll returns the trades of a market newer than its cursor, oldest first,
+// and advances the cursor past them.
+func (stream *TradeStream) poll(ctx context.Context, marketId string) ([]TradeEntry, error) {
+	var fresh []TradeEntry
+
+	stream.mutex.Lock()
+	cursor, ok := stream.cursors[marketId]
+	stream.mutex.Unlock()
+
+	if !ok {
+		trade, err := stream.client.GetTradesByMarketWithContext(ctx, marketId, "")
+		if err != nil {
+			return nil, err
+		}
+		stream.advance(marketId, &tradeCursor{seen: make(map[string]bool)}, trade.Entries)
+		return nil, nil
+	}
+
+	history := stream.client.TradeHistory(marketId, time.Time{}, cursor.timestamp)
+	for history.HasNext() {
+		entries, err := history.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !cursor.seen[tradeKey(entry)] {
+				fresh = append(fresh, entry)
+			}
+		}
+	}
+
+	for i, j := 0, len(fresh)-1; i < j; i, j = i+1, j-1 {
+		fresh[i], fresh[j] = fresh[j], fresh[i]
+	}
+
+	stream.advance(marketId, cursor, fresh)
+	return fresh, nil
+}
+
+func (stream *TradeStream) advance(marketId string, cursor *tradeCursor, entries []TradeEntry) {
+	latest := cursor.timestamp
+	for _, entry := range entries {
+		if entry.Timestamp.After(latest) {
+			latest = entry.Timestamp
+		}
+	}
+
+	seen := make(map[string]bool)
+	if latest.Equal(cursor.timestamp) {
+		for key := range cursor.seen {
+			seen[key] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Timestamp.Equal(latest) {
+			seen[tradeKey(entry)] = true
+		}
+	}
+
+	stream.mutex.Lock()
+	stream.cursors[marketId] = &tradeCursor{timestamp: latest, seen: seen}
+	stream.mutex.Unlock()
+}
@@ -0,0 +1,94 @@
+package buda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTape struct {
+	mutex  sync.Mutex
+	trades map[string][]string
+}
+
+func (tape *fakeTape) add(marketId string, timestamp int64, id int) {
+	tape.mutex.Lock()
+	defer tape.mutex.Unlock()
+	entry := fmt.Sprintf(`["%d", "0.1", "1000.0", "sell", %d]`, timestamp, id)
+	tape.trades[marketId] = append([]string{entry}, tape.trades[marketId]...)
+}
+
+func (tape *fakeTape) register(client *APIClient, marketId string) {
+	httpmock.RegisterResponder("GET", client.FormatResource(fmt.Sprintf(MarketTradesEndpoint, marketId)),
+		func(req *http.Request) (*http.Response, error) {
+			tape.mutex.Lock()
+			defer tape.mutex.Unlock()
+			body := fmt.Sprintf(`{"trades": {"market_id": %q, "entries": [%s]}}`, marketId, strings.Join(tape.trades[marketId], ","))
+			return httpmock.NewStringResponse(200, body), nil
+		})
+}
+
+func TestTradeStream_EmitsNewTradesInOrder(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tape := &fakeTape{trades: make(map[string][]string)}
+	tape.add("BTC-CLP", 1000, 1)
+	tape.add("ETH-CLP", 1500, 2)
+	tape.register(client, "BTC-CLP")
+	tape.register(client, "ETH-CLP")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := client.NewTradeStream(ctx, 5*time.Millisecond, "BTC-CLP", "ETH-CLP")
+
+	assert.Eventually(t, func() bool {
+		return stream.Cursor("BTC-CLP").Equal(time.Unix(1, 0)) && !stream.Cursor("ETH-CLP").IsZero()
+	}, time.Second, time.Millisecond)
+
+	tape.add("BTC-CLP", 2000, 3)
+	tape.add("ETH-CLP", 2000, 4)
+	tape.add("BTC-CLP", 3000, 5)
+
+	var ids []int64
+	for len(ids) < 3 {
+		select {
+		case trade := <-stream.Trades:
+			ids = append(ids, trade.ID)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for trades")
+		}
+	}
+	assert.Contains(t, [][]int64{{3, 4, 5}, {4, 3, 5}}, ids)
+	assert.Equal(t, time.Unix(3, 0).UTC(), stream.Cursor("BTC-CLP"))
+
+	cancel()
+	for range stream.Trades {
+	}
+	_, open := <-stream.Errors
+	assert.False(t, open)
+}
+
+func TestTradeStream_NonPositiveInterval(t *testing.T) {
+	client, _ := NewAPIClient("", "")
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tape := &fakeTape{trades: make(map[string][]string)}
+	tape.register(client, "BTC-CLP")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := client.NewTradeStream(ctx, 0, "BTC-CLP")
+	assert.Equal(t, DefaultPollInterval, stream.interval)
+
+	cancel()
+	for range stream.Trades {
+	}
+}
@@ -0,0 +1,116 @@
+package buda
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TradeEntry is a single trade, encoded by the API as
+// [timestamp, amount, price, direction, id] where the id may be missing.
+type TradeEntry struct {
+	Timestamp time.Time
+	Amount    decimal.Decimal
+	Price     decimal.Decimal
+	Direction TradeDirection
+	ID        int64
+}
+
+func parseMillis(data []byte) (time.Time, error) {
+	if string(data) == "null" {
+		return time.Time{}, nil
+	}
+
+	millis, err := strconv.ParseInt(string(bytes.Trim(data, `"`)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+}
+
+// FormatMillis renders a time the way the trades endpoint expects its
+// timestamp parameter.
+func FormatMillis(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+func (trade *Trade) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		MarketId      string          `json:"market_id"`
+		Timestamp     json.RawMessage `json:"timestamp"`
+		LastTimestamp json.RawMessage `json:"last_timestamp"`
+		Entries       []TradeEntry    `json:"entries"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	trade.MarketId = raw.MarketId
+	trade.Entries = raw.Entries
+
+	if len(raw.Timestamp) > 0 {
+		trade.Timestamp, err = parseMillis(raw.Timestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(raw.LastTimestamp) > 0 {
+		trade.LastTimestamp, err = parseMillis(raw.LastTimestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (entry *TradeEntry) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) < 4 {
+		return fmt.Errorf("trade entry has %d fields, expected at least 4", len(fields))
+	}
+
+	entry.Timestamp, err = parseMillis(fields[0])
+	if err != nil {
+		return err
+	}
+
+	entry.Amount, err = decimal.NewFromString(string(bytes.Trim(fields[1], `"`)))
+	if err != nil {
+		return err
+	}
+
+	entry.Price, err = decimal.NewFromString(string(bytes.Trim(fields[2], `"`)))
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(fields[3], &entry.Direction)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = 0
+	if len(fields) > 4 && string(fields[4]) != "null" {
+		entry.ID, err = strconv.ParseInt(string(bytes.Trim(fields[4], `"`)), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
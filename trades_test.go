@@ -0,0 +1,50 @@
+package buda
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrade_UnmarshalJSON(t *testing.T) {
+	var trades Trades
+	err := json.Unmarshal([]byte(`{"trades": {
+		"timestamp": "1476905551698",
+		"last_timestamp": "1476380738941",
+		"market_id": "BTC-CLP",
+		"entries": [
+			["1476905551687", "0.00984662", "435447.12", "buy", 3512],
+			["1476905551676", "3.01572553", "435283.3", "sell"]
+		]
+	}}`), &trades)
+	assert.NoError(t, err)
+
+	trade := trades.Trade
+	assert.Equal(t, time.Unix(0, 1476905551698*int64(time.Millisecond)).UTC(), trade.Timestamp)
+	assert.Equal(t, time.Unix(0, 1476380738941*int64(time.Millisecond)).UTC(), trade.LastTimestamp)
+	assert.Len(t, trade.Entries, 2)
+
+	first := trade.Entries[0]
+	assert.Equal(t, time.Unix(0, 1476905551687*int64(time.Millisecond)).UTC(), first.Timestamp)
+	assert.True(t, first.Amount.Equal(decimal.RequireFromString("0.00984662")))
+	assert.True(t, first.Price.Equal(decimal.RequireFromString("435447.12")))
+	assert.Equal(t, TradeDirectionBuy, first.Direction)
+	assert.Equal(t, int64(3512), first.ID)
+
+	assert.Equal(t, TradeDirectionSell, trade.Entries[1].Direction)
+	assert.Equal(t, int64(0), trade.Entries[1].ID)
+}
+
+func TestTradeEntry_UnmarshalJSONInvalid(t *testing.T) {
+	var entry TradeEntry
+	assert.Error(t, json.Unmarshal([]byte(`["1476905551687", "0.1", "435447.12"]`), &entry))
+	assert.Error(t, json.Unmarshal([]byte(`["1476905551687", "0.1", "435447.12", "hold"]`), &entry))
+	assert.Error(t, json.Unmarshal([]byte(`["yesterday", "0.1", "435447.12", "buy"]`), &entry))
+}
+
+func TestFormatMillis(t *testing.T) {
+	assert.Equal(t, "1476905551698", FormatMillis(time.Unix(0, 1476905551698*int64(time.Millisecond))))
+}